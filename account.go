@@ -0,0 +1,41 @@
+package ekdsend
+
+import "context"
+
+// AccountAPI provides access to account-level information
+type AccountAPI struct {
+	client *Client
+}
+
+// Reputation retrieves the sending reputation of the account's domains and IPs
+func (a *AccountAPI) Reputation(ctx context.Context) (*Reputation, error) {
+	var resp Reputation
+
+	err := a.client.Get(ctx, "/account/reputation", nil, envelope(&resp))
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// VerifyKey checks that the client's API key is valid and the API is
+// reachable, without sending anything, and describes the key. A rejected
+// key returns an *AuthenticationError.
+func (c *Client) VerifyKey(ctx context.Context) (*KeyInfo, error) {
+	var resp KeyInfo
+
+	err := c.Get(ctx, "/me", nil, envelope(&resp))
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Ping is VerifyKey for callers that only need the preflight check, e.g.
+// at startup
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.VerifyKey(ctx)
+	return err
+}
@@ -0,0 +1,86 @@
+package ekdsend
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// CheckAlignment performs a local DMARC alignment check on params and
+// returns a warning for every domain that does not align with the From
+// domain. The From domain is compared against the sending domains
+// configured with WithSendingDomains and against params.ReturnPath, using
+// relaxed (organizational domain) alignment. Only the domains passed to
+// WithSendingDomains are consulted, not the domains verified on the account;
+// without WithSendingDomains only the return path is checked. An empty
+// result means no misalignment was detected; it does not guarantee DMARC
+// will pass.
+func (e *EmailsAPI) CheckAlignment(params *SendEmailParams) []string {
+	var warnings []string
+
+	fromDomain, err := addressDomain(params.From)
+	if err != nil {
+		return []string{fmt.Sprintf("cannot check alignment: invalid From address %q", params.From)}
+	}
+
+	if len(e.client.sendingDomains) > 0 {
+		aligned := false
+		for _, domain := range e.client.sendingDomains {
+			if domainsAligned(fromDomain, domain) {
+				aligned = true
+				break
+			}
+		}
+		if !aligned {
+			warnings = append(warnings, fmt.Sprintf(
+				"From domain %q does not align with any configured sending domain (%s)",
+				fromDomain, strings.Join(e.client.sendingDomains, ", ")))
+		}
+	}
+
+	if params.ReturnPath != "" {
+		returnDomain, err := addressDomain(params.ReturnPath)
+		switch {
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("invalid ReturnPath address %q", params.ReturnPath))
+		case !domainsAligned(fromDomain, returnDomain):
+			warnings = append(warnings, fmt.Sprintf(
+				"From domain %q does not align with return-path domain %q; SPF alignment will fail",
+				fromDomain, returnDomain))
+		}
+	}
+
+	return warnings
+}
+
+// addressDomain returns the lowercased domain of an RFC 5322 address
+func addressDomain(address string) (string, error) {
+	addr, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", err
+	}
+	at := strings.LastIndex(addr.Address, "@")
+	if at < 0 {
+		return "", fmt.Errorf("address %q has no domain", address)
+	}
+	return strings.ToLower(addr.Address[at+1:]), nil
+}
+
+// domainsAligned reports whether a and b share an organizational domain
+func domainsAligned(a, b string) bool {
+	return organizationalDomain(a) == organizationalDomain(b)
+}
+
+// organizationalDomain returns the registrable part of a domain, as
+// determined by the public suffix list, e.g. example.co.uk for
+// mail.example.co.uk. Domains that are themselves public suffixes or that
+// have a single label are returned as is.
+func organizationalDomain(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if org, err := publicsuffix.EffectiveTLDPlusOne(domain); err == nil {
+		return org
+	}
+	return domain
+}
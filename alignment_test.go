@@ -0,0 +1,27 @@
+package ekdsend
+
+import "testing"
+
+func TestOrganizationalDomain(t *testing.T) {
+	tests := map[string]string{
+		"example.com":        "example.com",
+		"mail.example.com":   "example.com",
+		"Mail.Example.COM.":  "example.com",
+		"sap.io":             "sap.io",
+		"mail.sap.io":        "sap.io",
+		"links.bit.ly":       "bit.ly",
+		"news.abc.de":        "abc.de",
+		"example.co.uk":      "example.co.uk",
+		"mail.example.co.uk": "example.co.uk",
+		"a.b.example.com.au": "example.com.au",
+		"shop.example.co.za": "example.co.za",
+		"app.github.io":      "app.github.io",
+		"co.uk":              "co.uk",
+		"localhost":          "localhost",
+	}
+	for domain, want := range tests {
+		if got := organizationalDomain(domain); got != want {
+			t.Errorf("organizationalDomain(%q) = %q, want %q", domain, got, want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package ekdsend
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultMaxAttachments is the default limit on attachments per email
+const DefaultMaxAttachments = 20
+
+// WithMaxAttachments sets how many attachments an email may carry before
+// it is rejected locally with a *ValidationError, instead of failing at
+// the provider after the upload. Zero or a negative n disables the check.
+// Defaults to DefaultMaxAttachments.
+func WithMaxAttachments(n int) ClientOption {
+	return func(c *Client) {
+		c.maxAttachments = n
+	}
+}
+
+// checkAttachmentCount validates count against the client's limit
+func (c *Client) checkAttachmentCount(count int) error {
+	if c.maxAttachments <= 0 || count <= c.maxAttachments {
+		return nil
+	}
+	return newValidationError(
+		fmt.Sprintf("email has %d attachments, the limit is %d", count, c.maxAttachments),
+		map[string]interface{}{"attachments": count, "max_attachments": c.maxAttachments},
+	)
+}
+
+// NewAttachmentFromFile reads the file at path into an Attachment named
+// after its base name, with ContentType inferred from the extension
+func NewAttachmentFromFile(path string) (*Attachment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var size int64
+	if info, err := f.Stat(); err == nil {
+		size = info.Size()
+	}
+
+	return newAttachment(filepath.Base(path), f, size)
+}
+
+// NewAttachmentFromReader reads r into an Attachment with the given
+// filename, inferring ContentType from its extension
+func NewAttachmentFromReader(filename string, r io.Reader) (*Attachment, error) {
+	return newAttachment(filename, r, 0)
+}
+
+// NewInlineAttachment reads r into an inline Attachment with the given
+// Content-ID, to be referenced from the HTML body as "cid:" + cid
+func NewInlineAttachment(cid, filename string, r io.Reader) (*Attachment, error) {
+	attachment, err := newAttachment(filename, r, 0)
+	if err != nil {
+		return nil, err
+	}
+	attachment.ContentID = cid
+	attachment.Disposition = DispositionInline
+	return attachment, nil
+}
+
+// newAttachment streams r through a base64 encoder so the raw bytes are
+// never held in memory alongside their encoding. size, when known, is used
+// to allocate the encoded content once.
+func newAttachment(filename string, r io.Reader, size int64) (*Attachment, error) {
+	var content strings.Builder
+	if size > 0 {
+		content.Grow(base64.StdEncoding.EncodedLen(int(size)))
+	}
+
+	enc := base64.NewEncoder(base64.StdEncoding, &content)
+	if _, err := io.Copy(enc, r); err != nil {
+		return nil, fmt.Errorf("failed to read attachment %s: %w", filename, err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return &Attachment{
+		Filename:    filename,
+		Content:     content.String(),
+		ContentType: mime.TypeByExtension(filepath.Ext(filename)),
+	}, nil
+}
@@ -0,0 +1,90 @@
+package ekdsend
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// auditEntry is one line of the audit log
+type auditEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Method     string    `json:"method"`
+	Resource   string    `json:"resource"`
+	Recipients []string  `json:"recipients"`
+	MessageID  string    `json:"message_id,omitempty"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// auditLog serializes entries to an append-only writer
+type auditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// WithAuditLog writes one JSON line per send attempt to w, recording the
+// timestamp, HTTP method, resource, SHA-256 hashes of the recipients,
+// message ID and resulting status. Recipients are hashed so the log holds
+// no contact details. Each line is written with a single Write call and
+// flushed immediately when w has a Flush or Sync method.
+func WithAuditLog(w io.Writer) ClientOption {
+	return func(c *Client) {
+		c.auditLog = &auditLog{w: w}
+	}
+}
+
+// audit records the outcome of a send when an audit log is configured
+func (c *Client) audit(resource string, recipients []string, messageID, status string, err error) {
+	if c.auditLog == nil {
+		return
+	}
+
+	entry := auditEntry{
+		Timestamp:  c.clock.Now().UTC(),
+		Method:     http.MethodPost,
+		Resource:   resource,
+		Recipients: hashRecipients(recipients),
+		MessageID:  messageID,
+		Status:     status,
+	}
+	if err != nil {
+		entry.Status = "error"
+		entry.Error = err.Error()
+	}
+
+	if werr := c.auditLog.write(entry); werr != nil {
+		c.errorf("Audit log write failed: %v", werr)
+	}
+}
+
+func (a *auditLog) write(entry auditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, err := a.w.Write(line); err != nil {
+		return err
+	}
+	return flushWriter(a.w)
+}
+
+// hashRecipients returns the hex SHA-256 of each normalized recipient
+func hashRecipients(recipients []string) []string {
+	hashes := make([]string, len(recipients))
+	for i, recipient := range recipients {
+		sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(recipient))))
+		hashes[i] = hex.EncodeToString(sum[:])
+	}
+	return hashes
+}
@@ -0,0 +1,362 @@
+package ekdsend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultBatchFlushInterval = 100 * time.Millisecond
+	DefaultMaxBatchSize       = 100
+)
+
+// ErrSenderClosed is returned for emails submitted to a closed BatchingSender
+var ErrSenderClosed = errors.New("ekdsend: batching sender is closed")
+
+// BatchOption configures a batch or bulk send
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	failFast       bool
+	idempotencyKey string
+}
+
+// WithFailFast stops a batch at the first item that fails instead of
+// collecting a result for every item. Items processed before the failure
+// have already been sent and cannot be recalled, so this is not a
+// transaction: it only prevents the remaining items from being attempted.
+func WithFailFast() BatchOption {
+	return func(o *batchOptions) {
+		o.failFast = true
+	}
+}
+
+// WithBatchIdempotencyKey makes a batch safe to retry as a whole. Batches
+// larger than one request are split, so each request is sent with key
+// suffixed by the index of its first item, e.g. "import-42-0" and
+// "import-42-100"; repeating the batch with the same key and items lets
+// the API return the original result for every request that already
+// succeeded.
+func WithBatchIdempotencyKey(key string) BatchOption {
+	return func(o *batchOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+func newBatchOptions(opts []BatchOption) batchOptions {
+	var o batchOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// chunk returns the options for the batch request whose first item is at
+// offset in the overall batch
+func (o batchOptions) chunk(offset int) batchOptions {
+	if o.idempotencyKey != "" {
+		o.idempotencyKey = fmt.Sprintf("%s-%d", o.idempotencyKey, offset)
+	}
+	return o
+}
+
+// requestOptions returns the request options for a batch request
+func (o batchOptions) requestOptions(meta *ResponseMeta) []RequestOption {
+	opts := []RequestOption{WithResponseMeta(meta)}
+	if o.idempotencyKey != "" {
+		opts = append(opts, WithIdempotencyKey(o.idempotencyKey))
+	}
+	return opts
+}
+
+// BatchResult is the outcome of a batch send. Succeeded holds the created
+// objects in request order and Failed the items that were rejected.
+// Suppressed maps the index of each item that lost recipients to
+// WithAutoSuppressionFilter to the removed addresses.
+type BatchResult[T any] struct {
+	Succeeded  []T
+	Failed     []BatchError
+	Suppressed map[int][]string
+}
+
+// BatchError is a failed batch item. Index is the item's position in the
+// request.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e BatchError) Error() string {
+	return fmt.Sprintf("batch item %d: %v", e.Index, e.Err)
+}
+
+func (e BatchError) Unwrap() error {
+	return e.Err
+}
+
+// add records the results of n batch items, the first of which is at
+// offset in the overall request. Items missing from results are failed.
+func (r *BatchResult[T]) add(results []batchItem[T], offset, n int) {
+	sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+
+	answered := make([]bool, n)
+	for _, res := range results {
+		if res.Index < 0 || res.Index >= n || answered[res.Index] {
+			continue
+		}
+		answered[res.Index] = true
+
+		index := offset + res.Index
+		if len(res.Suppressed) > 0 {
+			if r.Suppressed == nil {
+				r.Suppressed = make(map[int][]string)
+			}
+			r.Suppressed[index] = res.Suppressed
+		}
+
+		switch {
+		case res.Error != nil:
+			r.Failed = append(r.Failed, BatchError{Index: index, Err: res.Error.err()})
+		case res.Data == nil:
+			r.Failed = append(r.Failed, BatchError{Index: index, Err: errors.New("empty result returned")})
+		default:
+			r.Succeeded = append(r.Succeeded, *res.Data)
+		}
+	}
+
+	for i, ok := range answered {
+		if !ok {
+			r.Failed = append(r.Failed, BatchError{Index: offset + i, Err: errors.New("no result returned")})
+		}
+	}
+	sort.Slice(r.Failed, func(i, j int) bool { return r.Failed[i].Index < r.Failed[j].Index })
+}
+
+// BatchingConfig configures a BatchingSender
+type BatchingConfig struct {
+	// FlushInterval is how long the first buffered email waits for others
+	// before the batch is dispatched. Defaults to DefaultBatchFlushInterval.
+	FlushInterval time.Duration
+
+	// MaxBatchSize dispatches the batch as soon as it holds this many
+	// emails. Defaults to DefaultMaxBatchSize; values above the API's limit
+	// of 100 emails per batch are capped to it.
+	MaxBatchSize int
+}
+
+// BatchSendResult is the outcome of an email sent through a BatchingSender.
+// Suppressed lists the recipients removed by WithAutoSuppressionFilter.
+type BatchSendResult struct {
+	Email      *Email
+	Err        error
+	Suppressed []string
+}
+
+// BatchingSender buffers individual emails for a short window and sends
+// them through the batch endpoint, trading a little latency for fewer
+// requests. It is safe for concurrent use.
+//
+// Buffered emails are dispatched with a background context; cancelling the
+// context passed to Send stops the caller waiting but does not withdraw the
+// email from its batch.
+type BatchingSender struct {
+	emails   *EmailsAPI
+	interval time.Duration
+	maxSize  int
+
+	mu       sync.Mutex
+	pending  []*pendingEmail
+	timer    *time.Timer
+	closed   bool
+	inflight sync.WaitGroup
+
+	// outstanding counts accepted emails whose result is not yet delivered
+	outstanding atomic.Int64
+}
+
+type pendingEmail struct {
+	params *SendEmailParams
+	done   chan BatchSendResult
+}
+
+// NewBatchingSender creates a BatchingSender that dispatches through client
+func NewBatchingSender(client *Client, config BatchingConfig) *BatchingSender {
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultBatchFlushInterval
+	}
+	if config.MaxBatchSize <= 0 {
+		config.MaxBatchSize = DefaultMaxBatchSize
+	}
+	if config.MaxBatchSize > maxEmailBatchSize {
+		config.MaxBatchSize = maxEmailBatchSize
+	}
+
+	b := &BatchingSender{
+		emails:   client.Emails,
+		interval: config.FlushInterval,
+		maxSize:  config.MaxBatchSize,
+	}
+	client.registerSender(b)
+	return b
+}
+
+// Send buffers an email and blocks until its batch has been dispatched or
+// ctx is done
+func (b *BatchingSender) Send(ctx context.Context, params *SendEmailParams) (*Email, error) {
+	select {
+	case res := <-b.SendAsync(params):
+		return res.Email, res.Err
+	case <-ctx.Done():
+		return nil, contextError(ctx.Err())
+	}
+}
+
+// SendAsync buffers an email and returns a channel that receives exactly
+// one result once its batch has been dispatched
+func (b *BatchingSender) SendAsync(params *SendEmailParams) <-chan BatchSendResult {
+	item := &pendingEmail{params: params, done: make(chan BatchSendResult, 1)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		item.done <- BatchSendResult{Err: ErrSenderClosed}
+		return item.done
+	}
+
+	// Reject oversized emails here so they cannot fail the whole batch
+	if err := b.emails.client.checkAttachmentCount(len(params.Attachments)); err != nil {
+		item.done <- BatchSendResult{Err: err}
+		return item.done
+	}
+
+	b.pending = append(b.pending, item)
+	b.outstanding.Add(1)
+	if len(b.pending) >= b.maxSize {
+		b.dispatchLocked(context.Background())
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(b.interval, b.flushTimer)
+	}
+
+	return item.done
+}
+
+// Flush dispatches any buffered emails immediately and waits until every
+// in-flight batch has completed or ctx is done. ctx only bounds the wait:
+// batches keep its values but are not cancelled with it, so emails already
+// handed to the API are not lost when ctx expires.
+func (b *BatchingSender) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	b.dispatchLocked(context.WithoutCancel(ctx))
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return contextError(ctx.Err())
+	}
+}
+
+// Outstanding returns the number of accepted emails whose batch has not
+// completed yet
+func (b *BatchingSender) Outstanding() int {
+	return int(b.outstanding.Load())
+}
+
+// pendingCount returns the number of buffered emails not yet dispatched
+func (b *BatchingSender) pendingCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending)
+}
+
+// Close flushes buffered emails and rejects any sent afterwards
+func (b *BatchingSender) Close(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	if err := b.Flush(ctx); err != nil {
+		return err
+	}
+	b.emails.client.unregisterSender(b)
+	return nil
+}
+
+func (b *BatchingSender) flushTimer() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dispatchLocked(context.Background())
+}
+
+// dispatchLocked hands the pending emails to a goroutine. b.mu must be held.
+func (b *BatchingSender) dispatchLocked(ctx context.Context) {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.pending) == 0 {
+		return
+	}
+
+	batch := b.pending
+	b.pending = nil
+
+	b.inflight.Add(1)
+	go func() {
+		defer b.inflight.Done()
+		b.dispatch(ctx, batch)
+	}()
+}
+
+func (b *BatchingSender) dispatch(ctx context.Context, batch []*pendingEmail) {
+	defer b.outstanding.Add(-int64(len(batch)))
+
+	params := make([]*SendEmailParams, len(batch))
+	for i, item := range batch {
+		params[i] = item.params
+	}
+
+	results, err := b.emails.batchSend(ctx, params, batchOptions{})
+	if err != nil {
+		for _, item := range batch {
+			item.done <- BatchSendResult{Err: err}
+		}
+		return
+	}
+
+	answered := make([]bool, len(batch))
+	for _, res := range results {
+		if res.Index < 0 || res.Index >= len(batch) || answered[res.Index] {
+			continue
+		}
+		answered[res.Index] = true
+
+		result := BatchSendResult{Email: res.Data, Suppressed: res.Suppressed}
+		switch {
+		case res.Error != nil:
+			result.Err = res.Error.err()
+		case res.Data == nil:
+			result.Err = fmt.Errorf("empty result returned for batch item %d", res.Index)
+		}
+		batch[res.Index].done <- result
+	}
+
+	for i, item := range batch {
+		if !answered[i] {
+			item.done <- BatchSendResult{Err: fmt.Errorf("no result returned for batch item %d", i)}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package ekdsend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// writeBatch answers a batch send with one successful item per email and
+// returns the number of emails
+func writeBatch(w http.ResponseWriter, r *http.Request) int {
+	var body struct {
+		Emails []json.RawMessage `json:"emails"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	items := make([]map[string]interface{}, len(body.Emails))
+	for i := range body.Emails {
+		items[i] = map[string]interface{}{
+			"index": i,
+			"data":  map[string]string{"id": fmt.Sprintf("em_%d", i+1), "status": "queued"},
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": items})
+	return len(items)
+}
+
+func TestNewBatchingSenderCapsBatchSize(t *testing.T) {
+	client := newTestClient(t, "http://api.ekdsend.test")
+
+	tests := map[int]int{0: DefaultMaxBatchSize, 10: 10, 100: 100, 500: maxEmailBatchSize}
+	for size, want := range tests {
+		b := NewBatchingSender(client, BatchingConfig{MaxBatchSize: size})
+		if b.maxSize != want {
+			t.Errorf("MaxBatchSize %d: maxSize = %d, want %d", size, b.maxSize, want)
+		}
+		b.Close(context.Background())
+	}
+}
+
+func TestBatchingSenderDrainTimeoutKeepsInFlightBatch(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-release
+		writeBatch(w, r)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	sender := NewBatchingSender(client, BatchingConfig{FlushInterval: time.Hour})
+	result := sender.SendAsync(testEmailParams())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := client.Drain(ctx)
+	var drainErr *DrainError
+	if !errors.As(err, &drainErr) {
+		t.Fatalf("Drain error = %v, want *DrainError", err)
+	}
+	if drainErr.Remaining != 0 || drainErr.InFlight != 1 {
+		t.Errorf("DrainError = %d remaining, %d in flight, want 0 and 1", drainErr.Remaining, drainErr.InFlight)
+	}
+	<-received
+	close(release)
+
+	select {
+	case res := <-result:
+		if res.Err != nil {
+			t.Fatalf("batch failed after the drain deadline: %v", res.Err)
+		}
+		if res.Email == nil || res.Email.ID != "em_1" {
+			t.Errorf("Email = %+v, want em_1", res.Email)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("batch did not complete")
+	}
+}
+
+func TestBatchSendClassifiesItemErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-request-id", "req_batch")
+		fmt.Fprint(w, `{"data":[
+			{"index":0,"error":{"message":"bad address","code":"INVALID_EMAIL","status_code":400,"details":{"to":"invalid"}}},
+			{"index":1,"error":{"message":"slow down","status_code":429,"retry_after":3}},
+			{"index":2,"data":{"id":"em_3","status":"queued"}}
+		]}`)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	emails := []*SendEmailParams{testEmailParams(), testEmailParams(), testEmailParams()}
+	result, err := client.Emails.BatchSend(context.Background(), emails)
+	if err != nil {
+		t.Fatalf("BatchSend: %v", err)
+	}
+	if len(result.Failed) != 2 || len(result.Succeeded) != 1 {
+		t.Fatalf("result = %+v, want 2 failed and 1 succeeded", result)
+	}
+
+	invalid := result.Failed[0].Err
+	var validationErr *ValidationError
+	if !errors.As(invalid, &validationErr) || !errors.Is(invalid, ErrValidation) {
+		t.Fatalf("item 0 error = %#v, want a ValidationError", invalid)
+	}
+	if validationErr.RequestID != "req_batch" || validationErr.Errors["to"] != "invalid" {
+		t.Errorf("item 0 RequestID, Errors = %q, %v", validationErr.RequestID, validationErr.Errors)
+	}
+
+	var rateErr *RateLimitError
+	if !errors.As(result.Failed[1].Err, &rateErr) || rateErr.RetryAfter != 3 {
+		t.Errorf("item 1 error = %#v, want a RateLimitError with RetryAfter 3", result.Failed[1].Err)
+	}
+}
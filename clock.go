@@ -0,0 +1,59 @@
+package ekdsend
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Clock abstracts time for the client. Retries, polling and rate limiting
+// all read and wait on the client's Clock, so tests can substitute a fake
+// implementation and run without real delays.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the Clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// WithClock sets the Clock used for retries, polling and rate limiting
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
+
+// sleep waits for d on the client's clock or until ctx is done
+func (c *Client) sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.clock.After(d):
+		return nil
+	}
+}
+
+// waitRateLimit blocks until the rate limiter grants a request, measuring
+// time with the client's clock
+func (c *Client) waitRateLimit(ctx context.Context) error {
+	now := c.clock.Now()
+	reservation := c.rateLimiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return errors.New("rate limiter does not allow any requests")
+	}
+
+	if err := c.sleep(ctx, reservation.DelayFrom(now)); err != nil {
+		reservation.CancelAt(c.clock.Now())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package ekdsend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultCompressionThreshold is the smallest request body, in bytes, that
+// WithCompression compresses
+const DefaultCompressionThreshold = 1024
+
+// WithCompression gzips request bodies of DefaultCompressionThreshold bytes
+// or more, such as emails with large HTML or attachments, and asks the API
+// for gzip responses. A body is sent uncompressed when gzip would not make
+// it smaller. Responses are decompressed according to their
+// Content-Encoding, so an API that answers uncompressed still works.
+func WithCompression() ClientOption {
+	return func(c *Client) {
+		c.compressionThreshold = DefaultCompressionThreshold
+	}
+}
+
+// compressBody gzips body when compression is enabled and worthwhile. It
+// reports whether the returned body is compressed.
+func (c *Client) compressBody(body []byte) ([]byte, bool) {
+	if c.compressionThreshold <= 0 || len(body) < c.compressionThreshold {
+		return body, false
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return body, false
+	}
+	if err := zw.Close(); err != nil {
+		return body, false
+	}
+	if buf.Len() >= len(body) {
+		return body, false
+	}
+	return buf.Bytes(), true
+}
+
+// decompressBody decodes a gzip-encoded response body. The transport only
+// does this itself when the SDK did not set Accept-Encoding.
+func decompressBody(header http.Header, body []byte) ([]byte, error) {
+	if !strings.EqualFold(strings.TrimSpace(header.Get("Content-Encoding")), "gzip") {
+		return body, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress response body: %w", err)
+	}
+	defer zr.Close()
+
+	decoded, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress response body: %w", err)
+	}
+	header.Del("Content-Encoding")
+	header.Del("Content-Length")
+	return decoded, nil
+}
@@ -0,0 +1,108 @@
+package ekdsend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithCompressionReducesRequestSize(t *testing.T) {
+	var encoding, acceptEncoding string
+	var wire, decoded []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		wire, _ = io.ReadAll(r.Body)
+
+		zr, err := gzip.NewReader(bytes.NewReader(wire))
+		if err != nil {
+			t.Errorf("request body is not gzip: %v", err)
+			http.Error(w, "bad gzip", http.StatusBadRequest)
+			return
+		}
+		decoded, _ = io.ReadAll(zr)
+
+		// Ignore Accept-Encoding and answer uncompressed
+		writeEmail(w, "em_1")
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL, WithCompression())
+	params := testEmailParams()
+
+	email, err := client.Emails.Send(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if email.ID != "em_1" {
+		t.Errorf("ID = %q, want em_1", email.ID)
+	}
+
+	if encoding != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", encoding)
+	}
+	if acceptEncoding != "gzip" {
+		t.Errorf("Accept-Encoding = %q, want gzip", acceptEncoding)
+	}
+
+	plain, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded, plain) {
+		t.Errorf("decompressed body = %q, want %q", decoded, plain)
+	}
+	if len(wire) >= len(plain) {
+		t.Errorf("compressed body is %d bytes, uncompressed %d", len(wire), len(plain))
+	}
+	t.Logf("request body gzipped from %d to %d bytes", len(plain), len(wire))
+}
+
+func TestWithCompressionSmallBody(t *testing.T) {
+	var encoding string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		io.Copy(io.Discard, r.Body)
+		writeEmail(w, "em_1")
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL, WithCompression())
+	params := testEmailParams()
+	params.HTML = "<p>Hi</p>"
+
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if encoding != "" {
+		t.Errorf("Content-Encoding = %q for a body under the threshold", encoding)
+	}
+}
+
+func TestWithCompressionGzipResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		json.NewEncoder(zw).Encode(map[string]interface{}{
+			"data": map[string]string{"id": "em_1", "status": "delivered"},
+		})
+		zw.Close()
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL, WithCompression())
+	email, err := client.Emails.Get(context.Background(), "em_1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if email.ID != "em_1" || email.Status != "delivered" {
+		t.Errorf("email = %+v, want em_1 delivered", email)
+	}
+}
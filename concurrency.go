@@ -0,0 +1,34 @@
+package ekdsend
+
+import "context"
+
+// WithMaxConcurrentRequests caps the number of HTTP requests in flight at
+// once across the client. It complements the rate limiter, which bounds
+// requests over time, by protecting a constrained transport from bursts.
+// Callers over the limit wait for a free slot or for their context to be
+// done. Backoff between retries does not hold a slot. Zero or a negative
+// n removes the cap.
+func WithMaxConcurrentRequests(n int) ClientOption {
+	return func(c *Client) {
+		if n <= 0 {
+			c.inflight = nil
+			return
+		}
+		c.inflight = make(chan struct{}, n)
+	}
+}
+
+// acquire waits for an in-flight request slot and returns the function
+// that releases it
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.inflight == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case c.inflight <- struct{}{}:
+		return func() { <-c.inflight }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
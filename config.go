@@ -0,0 +1,132 @@
+package ekdsend
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ClientConfig is a snapshot of a client's resolved, non-secret settings.
+// It is intended for diagnostics and is safe to log: the API key and proxy
+// password are masked, and only the names of default headers are
+// included.
+type ClientConfig struct {
+	APIKey                 string            `json:"api_key"`
+	BaseURL                string            `json:"base_url"`
+	UserAgent              string            `json:"user_agent"`
+	Timeout                time.Duration     `json:"timeout"`
+	OverallTimeout         time.Duration     `json:"overall_timeout,omitempty"`
+	AttemptTimeout         time.Duration     `json:"attempt_timeout,omitempty"`
+	RetryWindow            time.Duration     `json:"retry_window,omitempty"`
+	ConnectTimeout         time.Duration     `json:"connect_timeout,omitempty"`
+	TLSHandshakeTimeout    time.Duration     `json:"tls_handshake_timeout,omitempty"`
+	ResponseHeaderTimeout  time.Duration     `json:"response_header_timeout,omitempty"`
+	IdleConnTimeout        time.Duration     `json:"idle_conn_timeout,omitempty"`
+	RetryPolicy            RetryPolicy       `json:"retry_policy"`
+	RateLimit              float64           `json:"rate_limit"`
+	RateBurst              int               `json:"rate_burst"`
+	AdaptiveRateLimit      bool              `json:"adaptive_rate_limit"`
+	Debug                  bool              `json:"debug"`
+	AutoPlainText          bool              `json:"auto_plain_text"`
+	SendingDomains         []string          `json:"sending_domains,omitempty"`
+	FromPool               []string          `json:"from_pool,omitempty"`
+	RedirectPolicy         bool              `json:"redirect_policy"`
+	Simulated              bool              `json:"simulated"`
+	SuppressionFilter      bool              `json:"suppression_filter"`
+	SuppressionGuard       bool              `json:"suppression_guard"`
+	MaxAttachments         int               `json:"max_attachments"`
+	ArrayQueryFormat       string            `json:"array_query_format"`
+	MaxConcurrentRequests  int               `json:"max_concurrent_requests,omitempty"`
+	HedgeDelay             time.Duration     `json:"hedge_delay,omitempty"`
+	ETagCacheSize          int               `json:"etag_cache_size,omitempty"`
+	CompressionThreshold   int               `json:"compression_threshold,omitempty"`
+	UnknownStatusTerminal  bool              `json:"unknown_status_terminal"`
+	AuditLog               bool              `json:"audit_log"`
+	RequestRecorder        bool              `json:"request_recorder"`
+	EnvelopeKey            string            `json:"envelope_key"`
+	Logger                 bool              `json:"logger"`
+	SendHook               bool              `json:"send_hook"`
+	Metrics                bool              `json:"metrics"`
+	ClientValidation       bool              `json:"client_validation"`
+	RequireTestMode        bool              `json:"require_test_mode"`
+	RecipientNormalization bool              `json:"recipient_normalization"`
+	ProxyURL               string            `json:"proxy_url,omitempty"`
+	ProxyFunc              bool              `json:"proxy_func"`
+	DefaultMetadata        map[string]string `json:"default_metadata,omitempty"`
+	DefaultTags            []string          `json:"default_tags,omitempty"`
+	DefaultHeaders         []string          `json:"default_headers,omitempty"`
+}
+
+// Config returns the client's effective configuration with the API key
+// masked, e.g. "ek_live_****1234"
+func (c *Client) Config() ClientConfig {
+	var timeouts TimeoutConfig
+	if c.timeouts != nil {
+		timeouts = *c.timeouts
+	}
+
+	return ClientConfig{
+		APIKey:                 maskAPIKey(c.apiKey),
+		BaseURL:                c.baseURL,
+		UserAgent:              c.userAgent(),
+		Timeout:                c.httpClient.Timeout,
+		OverallTimeout:         c.overallTimeout,
+		AttemptTimeout:         c.attemptTimeout,
+		RetryWindow:            c.retryWindow,
+		ConnectTimeout:         timeouts.Connect,
+		TLSHandshakeTimeout:    timeouts.TLSHandshake,
+		ResponseHeaderTimeout:  timeouts.ResponseHeader,
+		IdleConnTimeout:        timeouts.IdleConn,
+		RetryPolicy:            c.retryPolicy,
+		RateLimit:              float64(c.rateLimiter.Limit()),
+		RateBurst:              c.rateLimiter.Burst(),
+		AdaptiveRateLimit:      c.adaptiveRateLimit,
+		Debug:                  c.debug,
+		AutoPlainText:          c.autoPlainText,
+		SendingDomains:         append([]string(nil), c.sendingDomains...),
+		FromPool:               c.fromPoolAddresses(),
+		RedirectPolicy:         c.redirectPolicy != nil,
+		Simulated:              c.simulate,
+		SuppressionFilter:      c.suppressionFilter,
+		SuppressionGuard:       c.suppressionGuard,
+		MaxAttachments:         c.maxAttachments,
+		ArrayQueryFormat:       string(c.arrayFormat),
+		MaxConcurrentRequests:  cap(c.inflight),
+		HedgeDelay:             c.hedgeDelay,
+		ETagCacheSize:          c.etagCacheSize(),
+		CompressionThreshold:   c.compressionThreshold,
+		UnknownStatusTerminal:  c.unknownStatusTerminal,
+		AuditLog:               c.auditLog != nil,
+		RequestRecorder:        c.recorder != nil,
+		EnvelopeKey:            c.envelopeKey,
+		Logger:                 c.logger != nil,
+		SendHook:               c.sendHook != nil,
+		Metrics:                c.metrics != nil,
+		ClientValidation:       !c.skipValidation,
+		RequireTestMode:        c.requireTestMode,
+		RecipientNormalization: !c.skipNormalization,
+		ProxyURL:               c.proxyURL,
+		ProxyFunc:              c.proxy != nil && c.proxyURL == "",
+		DefaultMetadata:        maps.Clone(c.defaultMetadata),
+		DefaultTags:            append([]string(nil), c.defaultTags...),
+		DefaultHeaders:         slices.Sorted(maps.Keys(c.defaultHeaders)),
+	}
+}
+
+// maskAPIKey keeps the key's mode prefix and last four characters
+func maskAPIKey(key string) string {
+	prefix := ""
+	for _, p := range []string{"ek_live_", "ek_test_"} {
+		if strings.HasPrefix(key, p) {
+			prefix = p
+			break
+		}
+	}
+
+	secret := strings.TrimPrefix(key, prefix)
+	if len(secret) <= 4 {
+		return prefix + "****"
+	}
+	return prefix + "****" + secret[len(secret)-4:]
+}
@@ -0,0 +1,119 @@
+package ekdsend
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConfigReportsOptions(t *testing.T) {
+	cfg := newTestClient(t, "http://api.ekdsend.test").Config()
+	if cfg.AuditLog || cfg.RequestRecorder || cfg.Logger || cfg.SendHook || cfg.Metrics || cfg.RequireTestMode {
+		t.Errorf("default Config() = %+v, want no optional features", cfg)
+	}
+
+	cfg = newTestClient(t, "http://api.ekdsend.test",
+		WithAuditLog(io.Discard),
+		WithRequestRecorder(io.Discard),
+		WithLogger(NewSlogLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))),
+		WithSendHook(func(SendRecord) {}),
+		WithMetrics(discardMetrics{}),
+		WithRequireTestMode(),
+	).Config()
+	if !cfg.AuditLog {
+		t.Error("AuditLog = false with WithAuditLog")
+	}
+	if !cfg.RequestRecorder {
+		t.Error("RequestRecorder = false with WithRequestRecorder")
+	}
+	if !cfg.Logger {
+		t.Error("Logger = false with WithLogger")
+	}
+	if !cfg.SendHook {
+		t.Error("SendHook = false with WithSendHook")
+	}
+	if !cfg.Metrics {
+		t.Error("Metrics = false with WithMetrics")
+	}
+	if !cfg.RequireTestMode {
+		t.Error("RequireTestMode = false with WithRequireTestMode")
+	}
+}
+
+func TestConfigRetryPolicy(t *testing.T) {
+	client := newTestClient(t, "http://api.ekdsend.test", WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Second,
+		MaxDelay:    time.Minute,
+		Jitter:      true,
+		RetryableFunc: func(*http.Response, error) bool {
+			return false
+		},
+	}))
+
+	policy := client.Config().RetryPolicy
+	if policy.MaxAttempts != 5 || policy.BaseDelay != time.Second || policy.MaxDelay != time.Minute ||
+		policy.Multiplier != DefaultRetryPolicy.Multiplier || !policy.Jitter {
+		t.Errorf("RetryPolicy = %+v", policy)
+	}
+
+	// The RetryableFunc must not stop the config from being logged as JSON
+	if _, err := json.Marshal(client.Config()); err != nil {
+		t.Errorf("json.Marshal(Config()): %v", err)
+	}
+}
+
+func TestConfigEnvelopeKey(t *testing.T) {
+	if got := newTestClient(t, "http://api.ekdsend.test").Config().EnvelopeKey; got != DefaultEnvelopeKey {
+		t.Errorf("default EnvelopeKey = %q, want %q", got, DefaultEnvelopeKey)
+	}
+	if got := newTestClient(t, "http://api.ekdsend.test", WithEnvelopeKey("")).Config().EnvelopeKey; got != "" {
+		t.Errorf("EnvelopeKey = %q, want empty for bare responses", got)
+	}
+}
+
+func TestConfigLocalChecks(t *testing.T) {
+	cfg := newTestClient(t, "http://api.ekdsend.test").Config()
+	if !cfg.ClientValidation || !cfg.RecipientNormalization {
+		t.Errorf("default ClientValidation, RecipientNormalization = %t, %t, want true, true",
+			cfg.ClientValidation, cfg.RecipientNormalization)
+	}
+
+	cfg = newTestClient(t, "http://api.ekdsend.test",
+		WithoutClientValidation(),
+		WithoutRecipientNormalization(),
+	).Config()
+	if cfg.ClientValidation || cfg.RecipientNormalization {
+		t.Errorf("ClientValidation, RecipientNormalization = %t, %t with both disabled, want false, false",
+			cfg.ClientValidation, cfg.RecipientNormalization)
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	client := newTestClient(t, "http://api.ekdsend.test",
+		WithDefaultMetadata(map[string]string{"tenant": "acme"}),
+		WithDefaultTags([]string{"production"}),
+	)
+
+	cfg := client.Config()
+	if !reflect.DeepEqual(cfg.DefaultMetadata, map[string]string{"tenant": "acme"}) {
+		t.Errorf("DefaultMetadata = %v", cfg.DefaultMetadata)
+	}
+	if !reflect.DeepEqual(cfg.DefaultTags, []string{"production"}) {
+		t.Errorf("DefaultTags = %v", cfg.DefaultTags)
+	}
+
+	cfg.DefaultMetadata["tenant"] = "changed"
+	cfg.DefaultTags[0] = "changed"
+	if client.defaultMetadata["tenant"] != "acme" || client.defaultTags[0] != "production" {
+		t.Error("modifying Config() changed the client's defaults")
+	}
+}
+
+type discardMetrics struct{}
+
+func (discardMetrics) ObserveRequest(string, int, time.Duration, int) {}
@@ -0,0 +1,75 @@
+package ekdsend
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// EngagementTier classifies how recently a contact engaged
+type EngagementTier string
+
+// Engagement tiers computed from a contact's most recent open or click
+const (
+	EngagementActive   EngagementTier = "active"
+	EngagementLapsing  EngagementTier = "lapsing"
+	EngagementInactive EngagementTier = "inactive"
+	EngagementNever    EngagementTier = "never"
+)
+
+// Engagement tier thresholds: a contact who engaged within
+// ActiveEngagementWindow is active, within LapsingEngagementWindow lapsing,
+// and inactive otherwise
+const (
+	ActiveEngagementWindow  = 30 * 24 * time.Hour
+	LapsingEngagementWindow = 90 * 24 * time.Hour
+)
+
+// ContactsAPI provides access to per-recipient information
+type ContactsAPI struct {
+	client *Client
+}
+
+// Engagement retrieves the open and click history of a recipient. Tier is
+// computed from the most recent open or click.
+func (c *ContactsAPI) Engagement(ctx context.Context, email string) (*Engagement, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, newValidationError("email is required", map[string]interface{}{"email": "required"})
+	}
+
+	var resp Engagement
+
+	err := c.client.Get(ctx, fmt.Sprintf("/contacts/%s/engagement", url.PathEscape(email)), nil, envelope(&resp))
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Tier = engagementTier(resp.LastEngagedAt(), c.client.clock.Now())
+	return &resp, nil
+}
+
+// LastEngagedAt returns the later of the last open and last click, or nil
+// if the recipient never engaged
+func (e *Engagement) LastEngagedAt() *time.Time {
+	last := e.LastOpenedAt
+	if e.LastClickedAt != nil && (last == nil || e.LastClickedAt.After(*last)) {
+		last = e.LastClickedAt
+	}
+	return last
+}
+
+func engagementTier(last *time.Time, now time.Time) EngagementTier {
+	switch {
+	case last == nil:
+		return EngagementNever
+	case now.Sub(*last) <= ActiveEngagementWindow:
+		return EngagementActive
+	case now.Sub(*last) <= LapsingEngagementWindow:
+		return EngagementLapsing
+	default:
+		return EngagementInactive
+	}
+}
@@ -0,0 +1,94 @@
+package ekdsend
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// keysetItem is implemented by list items that support keyset pagination
+type keysetItem interface {
+	keyset() (createdAt time.Time, id string)
+}
+
+func (e Email) keyset() (time.Time, string)     { return e.CreatedAt, e.ID }
+func (s SMS) keyset() (time.Time, string)       { return s.CreatedAt, s.ID }
+func (v VoiceCall) keyset() (time.Time, string) { return v.CreatedAt, v.ID }
+
+// encodeCursor builds an opaque cursor token from a record's creation time
+// and ID
+func encodeCursor(createdAt time.Time, id string) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor parses a token produced by encodeCursor
+func decodeCursor(token string) (time.Time, string, error) {
+	invalid := newValidationError("invalid pagination cursor", map[string]interface{}{"cursor": token})
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, "", invalid
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", invalid
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return time.Time{}, "", invalid
+	}
+	return t, id, nil
+}
+
+// setPageQuery sets keyset pagination params when a Before or After cursor
+// is given, and the offset otherwise
+func setPageQuery(query url.Values, offset int, before, after string) error {
+	if before == "" && after == "" {
+		query.Set("offset", strconv.Itoa(offset))
+		return nil
+	}
+
+	for prefix, token := range map[string]string{"before": before, "after": after} {
+		if token == "" {
+			continue
+		}
+		createdAt, id, err := decodeCursor(token)
+		if err != nil {
+			return err
+		}
+		query.Set(prefix+"_created_at", createdAt.Format(time.RFC3339Nano))
+		query.Set(prefix+"_id", id)
+	}
+	return nil
+}
+
+// FirstCursor returns the cursor token of the first item on the page, or ""
+// for an empty page. Pass it as After to fetch records newer than this page.
+func (p *PaginatedResponse[T]) FirstCursor() string {
+	if len(p.Data) == 0 {
+		return ""
+	}
+	return itemCursor(p.Data[0])
+}
+
+// LastCursor returns the cursor token of the last item on the page, or ""
+// for an empty page. Pass it as Before to continue a newest-first scan;
+// unlike NextOffset, records created during the scan do not shift the
+// following pages.
+func (p *PaginatedResponse[T]) LastCursor() string {
+	if len(p.Data) == 0 {
+		return ""
+	}
+	return itemCursor(p.Data[len(p.Data)-1])
+}
+
+func itemCursor(item any) string {
+	keyed, ok := item.(keysetItem)
+	if !ok {
+		return ""
+	}
+	return encodeCursor(keyed.keyset())
+}
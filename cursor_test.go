@@ -0,0 +1,129 @@
+package ekdsend
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.FixedZone("CET", 3600))
+
+	token := encodeCursor(createdAt, "em_1|2")
+	gotTime, gotID, err := decodeCursor(token)
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if !gotTime.Equal(createdAt) || gotID != "em_1|2" {
+		t.Errorf("decodeCursor = %s, %q, want %s, %q", gotTime, gotID, createdAt, "em_1|2")
+	}
+	if url.QueryEscape(token) != token {
+		t.Errorf("cursor %q is not URL-safe", token)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	for _, token := range []string{
+		"not base64!",
+		encodeCursorRaw("2024-03-01T12:30:00Z"),
+		encodeCursorRaw("2024-03-01T12:30:00Z|"),
+		encodeCursorRaw("yesterday|em_1"),
+	} {
+		if _, _, err := decodeCursor(token); !IsValidationError(err) {
+			t.Errorf("decodeCursor(%q) error = %v, want ValidationError", token, err)
+		}
+	}
+}
+
+// encodeCursorRaw encodes raw as a cursor token without validating it
+func encodeCursorRaw(raw string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func TestListBeforeCursor(t *testing.T) {
+	first := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var queries []url.Values
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		page := len(queries)
+
+		// Two full pages of two emails, then an empty page
+		data := []Email{}
+		if page <= 2 {
+			for i := 0; i < 2; i++ {
+				n := (page-1)*2 + i
+				data = append(data, Email{ID: "em_" + string(rune('a'+n)), CreatedAt: first.Add(-time.Duration(n) * time.Minute)})
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "limit": 2})
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	start := encodeCursor(first.Add(time.Minute), "em_start")
+	page, err := client.Emails.List(context.Background(), &ListEmailsParams{Limit: 2, Before: start})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	pages := 1
+	for {
+		next, ok, err := page.Next(context.Background())
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if !ok {
+			break
+		}
+		page = next
+		pages++
+	}
+	if pages != 3 {
+		t.Fatalf("listed %d pages, want 3", pages)
+	}
+
+	if q := queries[0]; q.Get("before_id") != "em_start" || q.Has("offset") {
+		t.Errorf("first query = %v, want before_id=em_start and no offset", q)
+	}
+	if q := queries[1]; q.Get("before_id") != "em_b" || q.Get("before_created_at") != first.Add(-time.Minute).Format(time.RFC3339Nano) {
+		t.Errorf("second query = %v, want it to continue before em_b", q)
+	}
+}
+
+func TestListServerCursor(t *testing.T) {
+	var queries []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		resp := map[string]interface{}{"data": []Email{{ID: "em_1"}}, "limit": 1, "total": 100}
+		if len(queries) == 1 {
+			resp["next_cursor"] = "opaque+token/1"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	page, err := client.Emails.List(context.Background(), &ListEmailsParams{Limit: 1, Offset: 5, Status: "sent"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if !page.HasMore() {
+		t.Fatal("HasMore = false with a next_cursor")
+	}
+
+	if _, ok, err := page.Next(context.Background()); !ok || err != nil {
+		t.Fatalf("Next = %t, %v", ok, err)
+	}
+	q := queries[1]
+	if q.Get("cursor") != "opaque+token/1" || q.Has("offset") || q.Get("status") != "sent" {
+		t.Errorf("second query = %v, want the server cursor, the original filters and no offset", q)
+	}
+}
@@ -0,0 +1,61 @@
+package ekdsend
+
+// WithDefaultMetadata adds metadata, such as a tenant ID, to every email,
+// SMS and call the client sends. Metadata given on a call wins on key
+// conflicts.
+func WithDefaultMetadata(metadata map[string]string) ClientOption {
+	return func(c *Client) {
+		if c.defaultMetadata == nil {
+			c.defaultMetadata = make(map[string]string, len(metadata))
+		}
+		for key, value := range metadata {
+			c.defaultMetadata[key] = value
+		}
+	}
+}
+
+// WithDefaultTags adds tags, such as the environment, to every email the
+// client sends, ahead of the tags given on the call. SMS and calls have no
+// tags and are unaffected.
+func WithDefaultTags(tags []string) ClientOption {
+	return func(c *Client) {
+		c.defaultTags = append(c.defaultTags, tags...)
+	}
+}
+
+// withDefaultMetadata returns metadata merged over the client's defaults.
+// metadata itself is never modified.
+func (c *Client) withDefaultMetadata(metadata map[string]string) map[string]string {
+	if len(c.defaultMetadata) == 0 {
+		return metadata
+	}
+
+	merged := make(map[string]string, len(c.defaultMetadata)+len(metadata))
+	for key, value := range c.defaultMetadata {
+		merged[key] = value
+	}
+	for key, value := range metadata {
+		merged[key] = value
+	}
+	return merged
+}
+
+// withDefaultTags returns the client's default tags followed by tags,
+// without duplicates. tags itself is never modified.
+func (c *Client) withDefaultTags(tags []string) []string {
+	if len(c.defaultTags) == 0 {
+		return tags
+	}
+
+	seen := make(map[string]bool, len(c.defaultTags)+len(tags))
+	merged := make([]string, 0, len(c.defaultTags)+len(tags))
+	for _, list := range [][]string{c.defaultTags, tags} {
+		for _, tag := range list {
+			if !seen[tag] {
+				seen[tag] = true
+				merged = append(merged, tag)
+			}
+		}
+	}
+	return merged
+}
@@ -0,0 +1,126 @@
+package ekdsend
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DNS record verification states
+const (
+	RecordVerified = "verified"
+	RecordPending  = "pending"
+	RecordFailed   = "failed"
+)
+
+// DomainsAPI provides access to sending domains and their verification
+type DomainsAPI struct {
+	client *Client
+}
+
+// Domain is a sending domain
+type Domain struct {
+	Name       string      `json:"name"`
+	Verified   bool        `json:"verified"`
+	DNSRecords []DNSRecord `json:"dns_records"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// DNSRecord is a DNS record the domain must publish. Purpose is "dkim",
+// "spf", "dmarc" or "return_path"; Status is one of RecordVerified,
+// RecordPending or RecordFailed.
+type DNSRecord struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Purpose string `json:"purpose"`
+	Status  string `json:"status"`
+}
+
+// MissingRecords returns the records that are not verified yet, i.e. the
+// DNS changes still to be made
+func (d *Domain) MissingRecords() []DNSRecord {
+	var missing []DNSRecord
+	for _, record := range d.DNSRecords {
+		if record.Status != RecordVerified {
+			missing = append(missing, record)
+		}
+	}
+	return missing
+}
+
+// DomainVerification is the authentication state of a sending domain.
+// DKIM, SPF and DMARC hold the status of each mechanism's records.
+type DomainVerification struct {
+	Domain      string      `json:"domain"`
+	DKIM        string      `json:"dkim"`
+	SPF         string      `json:"spf"`
+	DMARC       string      `json:"dmarc"`
+	ReadyToSend bool        `json:"ready_to_send"`
+	Records     []DNSRecord `json:"records"`
+	CheckedAt   time.Time   `json:"checked_at"`
+}
+
+// ListDomainsParams are the parameters for listing domains
+type ListDomainsParams struct {
+	Limit  int
+	Offset int
+}
+
+// List retrieves a paginated list of sending domains
+func (d *DomainsAPI) List(ctx context.Context, params *ListDomainsParams) (*PaginatedResponse[Domain], error) {
+	if params == nil {
+		params = &ListDomainsParams{Limit: 20, Offset: 0}
+	}
+
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(params.Limit))
+	query.Set("offset", strconv.Itoa(params.Offset))
+
+	var resp PaginatedResponse[Domain]
+	err := d.client.Get(ctx, "/domains", query, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.bindNext("", "", func(ctx context.Context, offset int, _ string) (*PaginatedResponse[Domain], error) {
+		next := *params
+		next.Offset = offset
+		return d.List(ctx, &next)
+	})
+
+	return &resp, nil
+}
+
+// Get retrieves a sending domain with its DNS records
+func (d *DomainsAPI) Get(ctx context.Context, domain string) (*Domain, error) {
+	var resp Domain
+
+	err := d.client.Get(ctx, fmt.Sprintf("/domains/%s", domainPath(domain)), nil, envelope(&resp))
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetVerificationStatus checks the domain's DKIM, SPF and DMARC records and
+// reports whether it is ready to send
+func (d *DomainsAPI) GetVerificationStatus(ctx context.Context, domain string) (*DomainVerification, error) {
+	var resp DomainVerification
+
+	err := d.client.Get(ctx, fmt.Sprintf("/domains/%s/verification", domainPath(domain)), nil, envelope(&resp))
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// domainPath normalizes domain for use as a path segment
+func domainPath(domain string) string {
+	return url.PathEscape(strings.ToLower(strings.TrimSpace(domain)))
+}
@@ -0,0 +1,84 @@
+package ekdsend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// download streams the body of a GET to path into w and returns the number
+// of bytes written. The body is copied as it arrives rather than buffered,
+// so the request is never retried.
+func (c *Client) download(ctx context.Context, path string, w io.Writer, opts ...RequestOption) (int64, error) {
+	rc := newRequestConfig(opts)
+
+	ctx, cancel := c.requestContext(ctx, rc)
+	defer cancel()
+
+	if err := c.waitRateLimit(ctx); err != nil {
+		if ctx.Err() != nil {
+			return 0, contextError(err)
+		}
+		return 0, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return 0, contextError(err)
+	}
+	defer release()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s", c.baseURL, path), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	req.Header.Set("User-Agent", c.userAgent())
+	c.applyHeaders(req, rc)
+	c.debugf("%s %s %v", http.MethodGet, path, c.logHeaders(req.Header))
+
+	started := c.clock.Now()
+	resp, err := c.httpClientFor(rc).Do(req)
+	if err != nil {
+		elapsed := c.clock.Now().Sub(started)
+		if ctx.Err() != nil {
+			return 0, contextError(ctx.Err())
+		}
+		c.observe(http.MethodGet, path, 0, elapsed, 1)
+		c.errorf("%s %s failed in %s: %v", http.MethodGet, path, elapsed, err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, &TimeoutError{Err: err}
+		}
+		return 0, &NetworkError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read response body: %w", err)
+		}
+		elapsed := c.clock.Now().Sub(started)
+		c.observe(http.MethodGet, path, resp.StatusCode, elapsed, 1)
+		if c.logger != nil {
+			c.debugf("Response (%d): %s", resp.StatusCode, c.logBody(body))
+		}
+		return 0, c.handleError(resp.StatusCode, body, resp.Header)
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	elapsed := c.clock.Now().Sub(started)
+	c.observe(http.MethodGet, path, resp.StatusCode, elapsed, 1)
+	c.debugf("%s %s -> %d in %s (bytes=%d, request_id=%s)", http.MethodGet, path, resp.StatusCode, elapsed, n, resp.Header.Get("x-request-id"))
+	if err != nil {
+		if ctx.Err() != nil {
+			return n, contextError(ctx.Err())
+		}
+		return n, fmt.Errorf("download interrupted after %d bytes: %w", n, err)
+	}
+
+	return n, nil
+}
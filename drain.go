@@ -0,0 +1,86 @@
+package ekdsend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DrainError is returned by Drain when ctx is done before every buffered
+// send has completed
+type DrainError struct {
+	// Remaining is the number of sends that were never dispatched
+	Remaining int
+
+	// InFlight is the number of sends already handed to the API whose
+	// batch had not completed. They are not cancelled and still complete
+	// after Drain returns.
+	InFlight int
+
+	Err error
+}
+
+func (e *DrainError) Error() string {
+	return fmt.Sprintf("EKDSend drain incomplete: %d sends not dispatched, %d in flight: %v", e.Remaining, e.InFlight, e.Err)
+}
+
+func (e *DrainError) Unwrap() error {
+	return e.Err
+}
+
+// Drain prepares the client for shutdown, e.g. on SIGTERM: every
+// BatchingSender created for the client is closed, its buffered emails are
+// dispatched, and Drain waits for the in-flight batches to complete. Sends
+// made after Drain starts are rejected with ErrSenderClosed. If ctx is
+// done first, Drain returns a *DrainError reporting how many sends were
+// not dispatched and how many were still in flight; in-flight batches are
+// not cancelled.
+func (c *Client) Drain(ctx context.Context) error {
+	c.sendersMu.Lock()
+	senders := make([]*BatchingSender, 0, len(c.senders))
+	for sender := range c.senders {
+		senders = append(senders, sender)
+	}
+	c.sendersMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, sender := range senders {
+		wg.Add(1)
+		go func(sender *BatchingSender) {
+			defer wg.Done()
+			_ = sender.Close(ctx)
+		}(sender)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		var remaining, inFlight int
+		for _, sender := range senders {
+			pending := sender.pendingCount()
+			remaining += pending
+			inFlight += sender.Outstanding() - pending
+		}
+		if remaining > 0 || inFlight > 0 {
+			return &DrainError{Remaining: remaining, InFlight: inFlight, Err: contextError(err)}
+		}
+	}
+	return nil
+}
+
+// registerSender tracks a BatchingSender so Drain can flush it
+func (c *Client) registerSender(b *BatchingSender) {
+	c.sendersMu.Lock()
+	defer c.sendersMu.Unlock()
+
+	if c.senders == nil {
+		c.senders = make(map[*BatchingSender]struct{})
+	}
+	c.senders[b] = struct{}{}
+}
+
+// unregisterSender stops tracking a BatchingSender once it has been closed
+func (c *Client) unregisterSender(b *BatchingSender) {
+	c.sendersMu.Lock()
+	defer c.sendersMu.Unlock()
+	delete(c.senders, b)
+}
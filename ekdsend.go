@@ -18,11 +18,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -32,9 +34,24 @@ const (
 	Version        = "1.1.0"
 	DefaultBaseURL = "https://es.ekddigital.com/v1"
 	DefaultTimeout = 30 * time.Second
+
+	defaultMaxRetries = 3
 )
 
-// Client is the EKDSend API client
+// Regional base URLs selectable with WithRegion
+var regionBaseURLs = map[string]string{
+	"us": DefaultBaseURL,
+	"eu": "https://es-eu.ekddigital.com/v1",
+}
+
+// Client is the EKDSend API client.
+//
+// A Client is safe for concurrent use by multiple goroutines and should be
+// shared rather than created per request. Its configuration is fixed once
+// New returns; state that changes afterwards (rate limiter, audit log,
+// request recorder) is synchronized internally. Params passed to API
+// methods are never modified, so the same params may be sent from several
+// goroutines at once.
 type Client struct {
 	// API key for authentication
 	apiKey string
@@ -45,16 +62,150 @@ type Client struct {
 	// HTTP client
 	httpClient *http.Client
 
+	// Transport from WithTransport, replacing the HTTP client's
+	transport http.RoundTripper
+
+	// Proxy selection from WithProxy or WithProxyFunc
+	proxy func(*http.Request) (*url.URL, error)
+
+	// Proxy from WithProxy with its password redacted, for Config
+	proxyURL string
+
 	// Rate limiter
 	rateLimiter *rate.Limiter
 
+	// Serializes rate limit changes from SetRateLimit and adaptRateLimit
+	rateMu sync.Mutex
+
+	// Lower the rate limit when the API answers 429
+	adaptiveRateLimit bool
+
+	// When adaptRateLimit last lowered the limit
+	rateLoweredAt time.Time
+
 	// Debug mode
 	debug bool
 
+	// Destination of diagnostic output; nil disables it
+	logger Logger
+
+	// JSON fields masked in logged bodies
+	redactedFields map[string]struct{}
+
+	// Called after every send
+	sendHook func(SendRecord)
+
+	// Receives an observation per API call
+	metrics MetricsCollector
+
+	// Skip local params validation before sends
+	skipValidation bool
+
+	// Send email recipients as given instead of normalizing them
+	skipNormalization bool
+
+	// Appended to the User-Agent header
+	userAgentSuffix string
+
+	// Merged into every send
+	defaultMetadata map[string]string
+	defaultTags     []string
+
+	// Sent with every request
+	defaultHeaders http.Header
+
+	// Request bodies this large or larger are gzipped; zero disables
+	// compression
+	compressionThreshold int
+
+	// Generate a text part for HTML-only emails
+	autoPlainText bool
+
+	// Verified sending domains used for alignment checks
+	sendingDomains []string
+
+	// Timeout for each individual HTTP attempt
+	attemptTimeout time.Duration
+
+	// Total time after which no further retries are started
+	retryWindow time.Duration
+
+	// Deadline for a whole API call including retries
+	overallTimeout time.Duration
+
+	// Transport-level timeouts from WithTimeouts
+	timeouts *TimeoutConfig
+
+	// Append-only log of sends
+	auditLog *auditLog
+
+	// Time source for retries, polling and rate limiting
+	clock Clock
+
+	// Return synthetic objects instead of sending
+	simulate bool
+
+	// Refuse sends with a live key
+	requireTestMode bool
+
+	// Lifecycle context merged into every request
+	baseCtx context.Context
+
+	// Drop suppressed recipients from batch sends
+	suppressionFilter bool
+
+	// Refuse email sends to suppressed recipients
+	suppressionGuard bool
+
+	// Stop polling on statuses the SDK does not recognize
+	unknownStatusTerminal bool
+
+	// Delay before a slow GET is hedged with a second request
+	hedgeDelay time.Duration
+
+	// Records round trips for later replay
+	recorder *recorder
+
+	// Attachments allowed per email; zero disables the check
+	maxAttachments int
+
+	// Encoding of slice parameters in list queries
+	arrayFormat ArrayQueryFormat
+
+	// Addresses used for emails sent without a From
+	fromPool *fromPool
+
+	// How failed requests are retried
+	retryPolicy RetryPolicy
+
+	// Semaphore bounding concurrent requests; nil means unbounded
+	inflight chan struct{}
+
+	// Key wrapping response payloads; empty for bare responses
+	envelopeKey string
+
+	// Cached GET responses revalidated with If-None-Match
+	etags *etagCache
+
+	// Batching senders flushed by Drain
+	sendersMu sync.Mutex
+	senders   map[*BatchingSender]struct{}
+
+	// Redirect policy applied to the HTTP client
+	redirectPolicy func(req *http.Request, via []*http.Request) error
+
+	// First error reported by a ClientOption
+	optErr error
+
 	// API Resources
-	Emails *EmailsAPI
-	SMS    *SMSAPI
-	Calls  *VoiceAPI
+	Emails       *EmailsAPI
+	SMS          *SMSAPI
+	Calls        *VoiceAPI
+	Account      *AccountAPI
+	Contacts     *ContactsAPI
+	Templates    *TemplatesAPI
+	Suppressions *SuppressionsAPI
+	Domains      *DomainsAPI
 }
 
 // ClientOption is a function that configures the client
@@ -67,6 +218,20 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
+// WithRegion routes requests through the regional endpoint for region
+// ("us" or "eu"), overriding the default base URL. An unknown region makes
+// New return an error.
+func WithRegion(region string) ClientOption {
+	return func(c *Client) {
+		baseURL, ok := regionBaseURLs[strings.ToLower(region)]
+		if !ok {
+			c.setOptErr(fmt.Errorf("unknown region %q", region))
+			return
+		}
+		c.baseURL = baseURL
+	}
+}
+
 // WithTimeout sets the HTTP client timeout
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(c *Client) {
@@ -74,14 +239,70 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
-// WithHTTPClient sets a custom HTTP client
+// WithAttemptTimeout bounds each individual HTTP attempt, independently of
+// the overall deadline carried by the request context. A slow attempt is
+// abandoned and retried instead of consuming the whole budget.
+func WithAttemptTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.attemptTimeout = timeout
+	}
+}
+
+// WithRetryWindow limits how long a request keeps retrying. No further
+// attempt is started once the next backoff would end after the window.
+func WithRetryWindow(window time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retryWindow = window
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client. It replaces the client as a
+// whole, including a timeout set earlier by WithTimeout; use WithTransport
+// to customize only how requests are sent.
 func WithHTTPClient(httpClient *http.Client) ClientOption {
 	return func(c *Client) {
 		c.httpClient = httpClient
 	}
 }
 
-// WithDebug enables debug logging
+// WithTransport sends requests through rt, e.g. for mTLS, a proxy or
+// request recording, while keeping the client's timeout and other
+// settings. It takes precedence over the transport of a client passed with
+// WithHTTPClient, whatever the option order. Transport-level timeouts from
+// WithTimeouts are applied to a clone of rt when it is an *http.Transport.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transport = rt
+	}
+}
+
+// WithRedirectPolicy controls how redirects are followed. It is installed
+// as the CheckRedirect function of the HTTP client (a copy is made when a
+// client was supplied with WithHTTPClient). Return http.ErrUseLastResponse
+// to stop at the redirect and inspect its Location header.
+//
+// By the time policy runs, the original headers have been copied onto req
+// and the Authorization header has been removed if the target host differs
+// from the API host, so the API key is never sent to a third party.
+// Headers set by policy on req are sent with the redirected request.
+func WithRedirectPolicy(policy func(req *http.Request, via []*http.Request) error) ClientOption {
+	return func(c *Client) {
+		c.redirectPolicy = policy
+	}
+}
+
+// WithBaseContext ties every request to ctx in addition to the context
+// passed to each call: a request is cancelled as soon as either context is
+// done, so cancelling ctx at shutdown aborts all in-flight calls. Values
+// and deadlines are taken from the per-call context only.
+func WithBaseContext(ctx context.Context) ClientOption {
+	return func(c *Client) {
+		c.baseCtx = ctx
+	}
+}
+
+// WithDebug enables debug logging to stdout, unless a Logger was set with
+// WithLogger
 func WithDebug(debug bool) ClientOption {
 	return func(c *Client) {
 		c.debug = debug
@@ -95,6 +316,25 @@ func WithRateLimiter(limiter *rate.Limiter) ClientOption {
 	}
 }
 
+// WithAutoPlainText generates a plain-text body for emails that only set
+// HTML. The conversion happens locally before the request is sent and can
+// be overridden per call with SendEmailParams.AutoPlainText.
+func WithAutoPlainText() ClientOption {
+	return func(c *Client) {
+		c.autoPlainText = true
+	}
+}
+
+// WithSendingDomains sets the domains the account sends from. They are
+// used by EmailsAPI.CheckAlignment to detect DMARC misalignment.
+func WithSendingDomains(domains ...string) ClientOption {
+	return func(c *Client) {
+		for _, domain := range domains {
+			c.sendingDomains = append(c.sendingDomains, strings.ToLower(strings.TrimSpace(domain)))
+		}
+	}
+}
+
 // New creates a new EKDSend client
 func New(apiKey string, opts ...ClientOption) (*Client, error) {
 	if apiKey == "" {
@@ -111,25 +351,98 @@ func New(apiKey string, opts ...ClientOption) (*Client, error) {
 		httpClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
-		rateLimiter: rate.NewLimiter(rate.Limit(100), 10), // 100 requests/second with burst of 10
+		rateLimiter:    rate.NewLimiter(rate.Limit(100), 10), // 100 requests/second with burst of 10
+		clock:          realClock{},
+		maxAttachments: DefaultMaxAttachments,
+		arrayFormat:    ArrayFormatCSV,
+		retryPolicy:    DefaultRetryPolicy,
+		envelopeKey:    DefaultEnvelopeKey,
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.optErr != nil {
+		return nil, c.optErr
+	}
+
+	if c.logger == nil && c.debug {
+		c.logger = defaultLogger()
+	}
+
+	httpClient := *c.httpClient
+	httpClient.CheckRedirect = c.checkRedirect
+	if c.transport != nil {
+		httpClient.Transport = c.transport
+	}
+	httpClient.Transport = c.timeouts.buildTransport(httpClient.Transport)
+	transport, err := c.proxyTransport(httpClient.Transport)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Transport = transport
+	if c.recorder != nil {
+		next := httpClient.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		httpClient.Transport = &recordingTransport{
+			next:     next,
+			recorder: c.recorder,
+			apiKey:   c.apiKey,
+			basePath: basePath(c.baseURL),
+		}
+	}
+	c.httpClient = &httpClient
 
 	// Initialize API resources
 	c.Emails = &EmailsAPI{client: c}
 	c.SMS = &SMSAPI{client: c}
 	c.Calls = &VoiceAPI{client: c}
+	c.Account = &AccountAPI{client: c}
+	c.Contacts = &ContactsAPI{client: c}
+	c.Templates = &TemplatesAPI{client: c}
+	c.Suppressions = &SuppressionsAPI{client: c}
+	c.Domains = &DomainsAPI{client: c}
 
 	return c, nil
 }
 
+// checkRedirect strips the Authorization header when a redirect leaves the
+// API host, then applies the configured redirect policy
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > 0 && !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		req.Header.Del("Authorization")
+	}
+
+	if c.redirectPolicy != nil {
+		return c.redirectPolicy(req, via)
+	}
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	return nil
+}
+
+// setOptErr records the first error raised while applying options
+func (c *Client) setOptErr(err error) {
+	if c.optErr == nil {
+		c.optErr = err
+	}
+}
+
 // Request makes an HTTP request to the API
-func (c *Client) Request(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+func (c *Client) Request(ctx context.Context, method, path string, body interface{}, result interface{}, opts ...RequestOption) error {
+	rc := newRequestConfig(opts)
+
+	ctx, cancel := c.requestContext(ctx, rc)
+	defer cancel()
+
 	// Wait for rate limiter
-	if err := c.rateLimiter.Wait(ctx); err != nil {
+	if err := c.waitRateLimit(ctx); err != nil {
+		if ctx.Err() != nil {
+			return contextError(err)
+		}
 		return fmt.Errorf("rate limiter error: %w", err)
 	}
 
@@ -138,17 +451,17 @@ func (c *Client) Request(ctx context.Context, method, path string, body interfac
 
 	// Prepare body
 	var bodyReader io.Reader
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		bodyReader = bytes.NewReader(jsonBody)
-
-		if c.debug {
-			fmt.Printf("[EKDSend] %s %s\n", method, path)
-			fmt.Printf("[EKDSend] Request: %s\n", string(jsonBody))
-		}
+	}
+	payload, compressed := c.compressBody(jsonBody)
+	if payload != nil {
+		bodyReader = bytes.NewReader(payload)
 	}
 
 	// Create request
@@ -161,62 +474,310 @@ func (c *Client) Request(ctx context.Context, method, path string, body interfac
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", fmt.Sprintf("ekdsend-go/%s", Version))
+	req.Header.Set("User-Agent", c.userAgent())
+	if c.compressionThreshold > 0 {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	c.applyHeaders(req, rc)
+
+	hc := c.httpClientFor(rc)
+	maxRetries := c.retryPolicy.MaxAttempts
+
+	// The same key is sent on every attempt so the API can deduplicate
+	// a write that succeeded before its response was lost
+	if rc.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", rc.idempotencyKey)
+	} else if method != http.MethodGet && method != http.MethodDelete && maxRetries > 0 {
+		req.Header.Set("Idempotency-Key", newUUID())
+	}
+
+	cached := c.etags.revalidate(req)
+
+	c.debugf("%s %s %v", method, path, c.logHeaders(req.Header))
+	if jsonBody != nil && c.logger != nil {
+		c.debugf("Request: %s", c.logBody(jsonBody))
+	}
+	if compressed {
+		c.debugf("Request body gzipped from %d to %d bytes", len(jsonBody), len(payload))
+	}
 
 	// Execute request with retries
-	var resp *http.Response
-	maxRetries := 3
+	var resp *response
+	var attempts int
+	started := c.clock.Now()
+
+	for attempt := 0; ; attempt++ {
+		attempts = attempt + 1
+		if c.hedgeDelay > 0 && method == http.MethodGet {
+			resp, err = c.hedgedAttempt(ctx, hc, req)
+		} else {
+			resp, err = c.attempt(ctx, hc, req)
+		}
+		if err != nil && ctx.Err() != nil {
+			return contextError(ctx.Err())
+		}
+		c.adaptRateLimit(resp)
 
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		resp, err = c.httpClient.Do(req)
-		if err != nil {
-			if attempt < maxRetries {
-				time.Sleep(time.Duration(1<<attempt) * time.Second)
-				continue
-			}
-			return fmt.Errorf("request failed: %w", err)
+		// Check for retryable failures and status codes
+		if attempt >= maxRetries || !c.retryPolicy.retryable(resp, err) {
+			break
 		}
 
-		// Check for retryable status codes
-		if resp.StatusCode == 429 || resp.StatusCode >= 500 {
-			if attempt < maxRetries {
-				resp.Body.Close()
-				time.Sleep(time.Duration(1<<attempt) * time.Second)
-				continue
+		// Wait at least as long as the API asked, falling back to the
+		// policy's backoff
+		delay := c.retryPolicy.delay(attempt)
+		if resp != nil {
+			if wait, ok := retryAfter(resp, c.clock.Now()); ok && wait > delay {
+				delay = wait
 			}
 		}
+		if c.retryWindow > 0 && c.clock.Now().Sub(started)+delay > c.retryWindow {
+			break
+		}
+		if err := c.sleep(ctx, delay); err != nil {
+			return contextError(err)
+		}
+	}
+	elapsed := c.clock.Now().Sub(started)
+	if err != nil {
+		c.observe(method, path, 0, elapsed, attempts)
+		c.errorf("%s %s failed after %d attempts in %s: %v", method, path, attempts, elapsed, err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &TimeoutError{Err: err}
+		}
+		return &NetworkError{Err: err}
+	}
+
+	c.observe(method, path, resp.StatusCode, elapsed, attempts)
+	c.debugf("%s %s -> %d in %s (attempts=%d, request_id=%s)", method, path, resp.StatusCode, elapsed, attempts, resp.Header.Get("x-request-id"))
+	rc.recordMeta(resp, attempts, elapsed)
+
+	// A 304 is answered from the cache rather than parsed
+	resp = c.etags.resolve(req, cached, resp)
+	return c.decodeResponse(resp, result)
+}
+
+// requestStream sends a pre-encoded body such as a multipart form. The body
+// is streamed and cannot be replayed, so the request is never retried.
+func (c *Client) requestStream(ctx context.Context, method, path, contentType string, body io.Reader, result interface{}, opts ...RequestOption) error {
+	rc := newRequestConfig(opts)
+
+	ctx, cancel := c.requestContext(ctx, rc)
+	defer cancel()
+
+	if err := c.waitRateLimit(ctx); err != nil {
+		if ctx.Err() != nil {
+			return contextError(err)
+		}
+		return fmt.Errorf("rate limiter error: %w", err)
+	}
 
-		break
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s%s", c.baseURL, path), body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent())
+	c.applyHeaders(req, rc)
+	if rc.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", rc.idempotencyKey)
+	}
+	c.debugf("%s %s %v", method, path, c.logHeaders(req.Header))
+
+	started := c.clock.Now()
+	resp, err := c.attempt(ctx, c.httpClientFor(rc), req)
+	elapsed := c.clock.Now().Sub(started)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		if ctx.Err() != nil {
+			return contextError(ctx.Err())
+		}
+		c.observe(method, path, 0, elapsed, 1)
+		c.errorf("%s %s failed in %s: %v", method, path, elapsed, err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &TimeoutError{Err: err}
+		}
+		return &NetworkError{Err: err}
 	}
 
-	if c.debug {
-		fmt.Printf("[EKDSend] Response (%d): %s\n", resp.StatusCode, string(respBody))
+	c.adaptRateLimit(resp)
+	c.observe(method, path, resp.StatusCode, elapsed, 1)
+	c.debugf("%s %s -> %d in %s (request_id=%s)", method, path, resp.StatusCode, elapsed, resp.Header.Get("x-request-id"))
+	rc.recordMeta(resp, 1, elapsed)
+	return c.decodeResponse(resp, result)
+}
+
+// requestContext applies the base context and the overall timeout, or the
+// per-request timeout when one is set, to ctx
+func (c *Client) requestContext(ctx context.Context, rc requestConfig) (context.Context, context.CancelFunc) {
+	var cancels []context.CancelFunc
+	if c.baseCtx != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = mergeContext(ctx, c.baseCtx)
+		cancels = append(cancels, cancel)
+	}
+	timeout := c.overallTimeout
+	if rc.timeout > 0 {
+		timeout = rc.timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		cancels = append(cancels, cancel)
+	}
+
+	return ctx, func() {
+		for i := len(cancels) - 1; i >= 0; i-- {
+			cancels[i]()
+		}
+	}
+}
+
+// decodeResponse converts error statuses into errors and unmarshals
+// successful responses into result
+func (c *Client) decodeResponse(resp *response, result interface{}) error {
+	if c.logger != nil {
+		c.debugf("Response (%d): %s", resp.StatusCode, c.logBody(resp.Body))
 	}
 
 	// Handle error responses
 	if resp.StatusCode >= 400 {
-		return c.handleError(resp.StatusCode, respBody, resp.Header.Get("x-request-id"))
+		return c.handleError(resp.StatusCode, resp.Body, resp.Header)
+	}
+
+	// A 304 the ETag cache could not answer, e.g. to an If-None-Match set
+	// with WithRequestHeaders, has no body to decode; treating it as
+	// success would return an empty result
+	if resp.StatusCode == http.StatusNotModified {
+		return &EKDSendError{
+			Message:    "API answered 304 Not Modified but no cached response is available",
+			Code:       "NOT_MODIFIED",
+			StatusCode: resp.StatusCode,
+			RequestID:  resp.Header.Get("x-request-id"),
+		}
 	}
 
 	// Parse response
-	if result != nil && len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, result); err != nil {
+	if result != nil && len(resp.Body) > 0 {
+		target := result
+		var err error
+		if env, ok := result.(*enveloped); ok {
+			target = env.v
+			err = c.unwrap(resp.Body, env.v)
+		} else {
+			err = json.Unmarshal(resp.Body, result)
+		}
+		if err != nil {
 			return fmt.Errorf("failed to parse response: %w", err)
 		}
+
+		if carrier, ok := target.(requestIDCarrier); ok {
+			carrier.setRequestID(resp.Header.Get("x-request-id"))
+		}
 	}
 
 	return nil
 }
 
-// handleError parses and returns the appropriate error type
-func (c *Client) handleError(statusCode int, body []byte, requestID string) error {
+// response is an HTTP response whose body has been read in full
+type response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// attempt performs a single HTTP round trip, bounded by the per-attempt
+// timeout when one is configured
+func (c *Client) attempt(ctx context.Context, hc *http.Client, req *http.Request) (*response, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if c.attemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.attemptTimeout)
+		defer cancel()
+	}
+
+	// Give every attempt a fresh copy of the body; a reader consumed by an
+	// earlier attempt would otherwise be resent empty
+	attemptReq := req.WithContext(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		attemptReq.Body = body
+	}
+
+	resp, err := hc.Do(attemptReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if body, err = decompressBody(resp.Header, body); err != nil {
+		return nil, err
+	}
+
+	return &response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}, nil
+}
+
+// mergeContext derives a context from ctx that is also cancelled when base
+// is done. The cancellation cause of base is preserved.
+func mergeContext(ctx, base context.Context) (context.Context, context.CancelFunc) {
+	merged, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(base, func() {
+		cancel(context.Cause(base))
+	})
+
+	return merged, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+// contextError classifies a context error: deadlines become a TimeoutError,
+// cancellations are returned as-is
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &TimeoutError{Err: err}
+	}
+	return err
+}
+
+// maxRawErrorBody caps how much of a non-JSON error body is kept in
+// EKDSendError.RawBody
+const maxRawErrorBody = 1024
+
+// rawErrorBody returns the start of body for EKDSendError.RawBody
+func rawErrorBody(body []byte) string {
+	if len(body) > maxRawErrorBody {
+		body = body[:maxRawErrorBody]
+	}
+	return strings.ToValidUTF8(string(body), "")
+}
+
+// handleError parses and returns the appropriate error type. The type
+// follows the status code even when the body is not JSON, such as a
+// proxy's HTML error page; RawBody and ContentType are then set instead of
+// the API's message and code.
+func (c *Client) handleError(statusCode int, body []byte, header http.Header) error {
 	var errResp struct {
 		Error struct {
 			Message    string                 `json:"message"`
@@ -226,78 +787,77 @@ func (c *Client) handleError(statusCode int, body []byte, requestID string) erro
 		} `json:"error"`
 	}
 
+	base := EKDSendError{StatusCode: statusCode, RequestID: header.Get("x-request-id")}
 	if err := json.Unmarshal(body, &errResp); err != nil {
-		return &EKDSendError{
-			Message:    "API request failed",
-			StatusCode: statusCode,
-			Code:       "UNKNOWN_ERROR",
-			RequestID:  requestID,
-		}
+		base.Message = "API request failed"
+		base.Code = "UNKNOWN_ERROR"
+		base.RawBody = rawErrorBody(body)
+		base.ContentType = header.Get("Content-Type")
+	} else {
+		base.Message = errResp.Error.Message
+		base.Code = errResp.Error.Code
 	}
 
-	switch statusCode {
-	case 400:
-		return &ValidationError{
-			EKDSendError: EKDSendError{
-				Message:    errResp.Error.Message,
-				StatusCode: 400,
-				Code:       "VALIDATION_ERROR",
-				RequestID:  requestID,
-			},
-			Errors: errResp.Error.Details,
-		}
-	case 401:
-		return &AuthenticationError{
-			EKDSendError: EKDSendError{
-				Message:    errResp.Error.Message,
-				StatusCode: 401,
-				Code:       "AUTHENTICATION_ERROR",
-				RequestID:  requestID,
-			},
-		}
-	case 404:
-		return &NotFoundError{
-			EKDSendError: EKDSendError{
-				Message:    errResp.Error.Message,
-				StatusCode: 404,
-				Code:       errResp.Error.Code,
-				RequestID:  requestID,
-			},
-		}
-	case 429:
-		return &RateLimitError{
-			EKDSendError: EKDSendError{
-				Message:    errResp.Error.Message,
-				StatusCode: 429,
-				Code:       "RATE_LIMIT_EXCEEDED",
-				RequestID:  requestID,
-			},
-			RetryAfter: errResp.Error.RetryAfter,
+	seconds := errResp.Error.RetryAfter
+	if statusCode == 429 && seconds == 0 {
+		if d, ok := parseRetryAfter(header.Get("Retry-After"), c.clock.Now()); ok {
+			seconds = int((d + time.Second - 1) / time.Second)
 		}
+	}
+	return classifyError(base, errResp.Error.Details, seconds)
+}
+
+// classifyError wraps base in the error type for its status code, so API
+// errors and rejected batch items are matched by the same Is* helpers and
+// sentinels
+func classifyError(base EKDSendError, details map[string]interface{}, retryAfter int) error {
+	switch statusCode := base.StatusCode; {
+	case statusCode == 400:
+		base.Code = "VALIDATION_ERROR"
+		return &ValidationError{EKDSendError: base, Errors: details}
+	case statusCode == 401:
+		base.Code = "AUTHENTICATION_ERROR"
+		return &AuthenticationError{EKDSendError: base}
+	case statusCode == 403:
+		return &PermissionError{EKDSendError: base}
+	case statusCode == 404:
+		return &NotFoundError{EKDSendError: base}
+	case statusCode == 409:
+		return &ConflictError{EKDSendError: base}
+	case statusCode == 429:
+		base.Code = "RATE_LIMIT_EXCEEDED"
+		return &RateLimitError{EKDSendError: base, RetryAfter: retryAfter}
+	case statusCode >= 500:
+		return &ServerError{EKDSendError: base}
 	default:
-		return &EKDSendError{
-			Message:    errResp.Error.Message,
-			StatusCode: statusCode,
-			Code:       errResp.Error.Code,
-			RequestID:  requestID,
-		}
+		return &base
 	}
 }
 
 // Get makes a GET request with query parameters
-func (c *Client) Get(ctx context.Context, path string, params url.Values, result interface{}) error {
+func (c *Client) Get(ctx context.Context, path string, params url.Values, result interface{}, opts ...RequestOption) error {
 	if len(params) > 0 {
 		path = fmt.Sprintf("%s?%s", path, params.Encode())
 	}
-	return c.Request(ctx, http.MethodGet, path, nil, result)
+	return c.Request(ctx, http.MethodGet, path, nil, result, opts...)
 }
 
 // Post makes a POST request
-func (c *Client) Post(ctx context.Context, path string, body interface{}, result interface{}) error {
-	return c.Request(ctx, http.MethodPost, path, body, result)
+func (c *Client) Post(ctx context.Context, path string, body interface{}, result interface{}, opts ...RequestOption) error {
+	return c.Request(ctx, http.MethodPost, path, body, result, opts...)
+}
+
+// Put makes a PUT request
+func (c *Client) Put(ctx context.Context, path string, body interface{}, result interface{}, opts ...RequestOption) error {
+	return c.Request(ctx, http.MethodPut, path, body, result, opts...)
+}
+
+// Patch makes a PATCH request
+func (c *Client) Patch(ctx context.Context, path string, body interface{}, result interface{}, opts ...RequestOption) error {
+	return c.Request(ctx, http.MethodPatch, path, body, result, opts...)
 }
 
 // Delete makes a DELETE request
-func (c *Client) Delete(ctx context.Context, path string, result interface{}) error {
-	return c.Request(ctx, http.MethodDelete, path, nil, result)
+func (c *Client) Delete(ctx context.Context, path string, result interface{}, opts ...RequestOption) error {
+	return c.Request(ctx, http.MethodDelete, path, nil, result, opts...)
 }
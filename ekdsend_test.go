@@ -0,0 +1,182 @@
+package ekdsend
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testAPIKey = "ek_test_123"
+
+// newTestClient returns a client pointed at baseURL with fast retries
+func newTestClient(t *testing.T, baseURL string, opts ...ClientOption) *Client {
+	t.Helper()
+
+	opts = append([]ClientOption{
+		WithBaseURL(baseURL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+	}, opts...)
+	client, err := New(testAPIKey, opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return client
+}
+
+// testEmailParams returns params that pass local validation
+func testEmailParams() *SendEmailParams {
+	return &SendEmailParams{
+		From:    "sender@example.com",
+		To:      []string{"recipient@example.com"},
+		Subject: "Hello",
+		HTML:    "<p>" + strings.Repeat("Hello there. ", 200) + "</p>",
+	}
+}
+
+// writeEmail writes a successful email response
+func writeEmail(w http.ResponseWriter, id string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": map[string]string{"id": id, "status": "queued"},
+	})
+}
+
+func TestRequestRetryResendsFullBody(t *testing.T) {
+	var mu sync.Mutex
+	var bodies [][]byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading body: %v", err)
+		}
+
+		mu.Lock()
+		bodies = append(bodies, body)
+		n := len(bodies)
+		mu.Unlock()
+
+		if n <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeEmail(w, "em_1")
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	params := testEmailParams()
+
+	email, err := client.Emails.Send(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if email.ID != "em_1" {
+		t.Errorf("ID = %q, want em_1", email.ID)
+	}
+
+	want, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("server received %d requests, want 3", len(bodies))
+	}
+	for i, body := range bodies {
+		if string(body) != string(want) {
+			t.Errorf("attempt %d body = %q, want %q", i+1, body, want)
+		}
+	}
+}
+
+func TestRedirectAuthorization(t *testing.T) {
+	var gotAuth string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		writeEmail(w, "em_1")
+	}))
+	defer target.Close()
+
+	var sameHostAuth string
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/emails/cross":
+			http.Redirect(w, r, target.URL+"/emails/em_1", http.StatusFound)
+		case "/emails/same":
+			http.Redirect(w, r, "/emails/moved", http.StatusFound)
+		case "/emails/moved":
+			sameHostAuth = r.Header.Get("Authorization")
+			writeEmail(w, "em_1")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer origin.Close()
+
+	client := newTestClient(t, origin.URL)
+
+	if _, err := client.Emails.Get(context.Background(), "cross"); err != nil {
+		t.Fatalf("Get cross-host: %v", err)
+	}
+	if gotAuth != "" {
+		t.Errorf("cross-host redirect sent Authorization %q", gotAuth)
+	}
+
+	if _, err := client.Emails.Get(context.Background(), "same"); err != nil {
+		t.Fatalf("Get same-host: %v", err)
+	}
+	if want := "Bearer " + testAPIKey; sameHostAuth != want {
+		t.Errorf("same-host redirect Authorization = %q, want %q", sameHostAuth, want)
+	}
+}
+
+// TestClientConcurrentUse is meant to run under go test -race
+func TestClientConcurrentUse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		writeEmail(w, "em_1")
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL,
+		WithDefaultMetadata(map[string]string{"app": "test"}),
+		WithDefaultTags([]string{"default"}),
+		WithAdaptiveRateLimit(),
+	)
+	client.SetRateLimit(10000, 100)
+
+	params := testEmailParams()
+	params.Metadata = map[string]string{"user": "42"}
+	before := *params
+	before.Metadata = map[string]string{"user": "42"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if _, err := client.Emails.Send(context.Background(), params); err != nil {
+					t.Errorf("Send: %v", err)
+					return
+				}
+				if _, err := client.Emails.Get(context.Background(), "em_1"); err != nil {
+					t.Errorf("Get: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !reflect.DeepEqual(*params, before) {
+		t.Errorf("Send modified params: got %+v, want %+v", *params, before)
+	}
+}
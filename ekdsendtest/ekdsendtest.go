@@ -0,0 +1,257 @@
+// Package ekdsendtest provides a mock EKDSend client for testing code that
+// uses the SDK, without a network or an httptest.Server.
+//
+//	client, mock := ekdsendtest.NewMockClient()
+//	mock.Enqueue("POST", "/emails",
+//		ekdsendtest.TooManyRequests(0),
+//		ekdsendtest.Data(200, map[string]any{"id": "em_1", "status": "queued"}),
+//	)
+//
+//	email, err := client.Emails.Send(ctx, params)
+//
+//	req, _ := mock.LastRequest()
+//	var sent ekdsend.SendEmailParams
+//	req.Decode(&sent)
+package ekdsendtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ekdsend "github.com/ekddigital/ekdsend-go"
+)
+
+// mockAPIKey is the API key of clients returned by NewMockClient
+const mockAPIKey = "ek_test_mock"
+
+// Reply is a canned response served by a Mock. When Err is set the request
+// fails at the transport level, as a dropped connection would, and the
+// other fields are ignored.
+type Reply struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+	Err        error
+}
+
+// Data replies with status and v wrapped in the API's {"data": ...}
+// envelope
+func Data(status int, v interface{}) Reply {
+	body, err := json.Marshal(map[string]interface{}{"data": v})
+	if err != nil {
+		panic(fmt.Sprintf("ekdsendtest: cannot encode reply: %v", err))
+	}
+	return Reply{StatusCode: status, Header: jsonHeader(), Body: string(body)}
+}
+
+// Error replies with an API error of the given status, code and message
+func Error(status int, code, message string) Reply {
+	body, _ := json.Marshal(map[string]interface{}{
+		"error": map[string]string{"code": code, "message": message},
+	})
+	return Reply{StatusCode: status, Header: jsonHeader(), Body: string(body)}
+}
+
+// TooManyRequests replies with a 429. A positive retryAfter is sent as the
+// Retry-After header, in seconds, which the client waits before retrying.
+func TooManyRequests(retryAfter int) Reply {
+	reply := Error(http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", "rate limit exceeded")
+	if retryAfter > 0 {
+		reply.Header.Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+	return reply
+}
+
+// ServerError replies with a 500
+func ServerError() Reply {
+	return Error(http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+}
+
+// NetworkError fails the request with err before any response is received
+func NetworkError(err error) Reply {
+	return Reply{Err: err}
+}
+
+func jsonHeader() http.Header {
+	return http.Header{"Content-Type": {"application/json"}}
+}
+
+// Request is a request received by a Mock
+type Request struct {
+	Method string
+
+	// Path is relative to the client's base URL, e.g. "/emails/em_1"
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Decode unmarshals the JSON request body into v
+func (r Request) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Mock answers a client's requests with enqueued replies and records every
+// request it receives. It is safe for concurrent use.
+type Mock struct {
+	mu       sync.Mutex
+	replies  map[string][]Reply
+	requests []Request
+	basePath string
+}
+
+// NewMockClient returns a client whose requests are answered by the
+// returned Mock instead of the API. Retries back off for a millisecond
+// rather than seconds so retry paths run quickly; a Retry-After header
+// still applies. opts are applied after the mock's own settings, but must
+// not replace the HTTP client. NewMockClient panics if an option is
+// invalid.
+func NewMockClient(opts ...ekdsend.ClientOption) (*ekdsend.Client, *Mock) {
+	m := &Mock{replies: map[string][]Reply{}}
+
+	retry := ekdsend.DefaultRetryPolicy
+	retry.BaseDelay = time.Millisecond
+	retry.MaxDelay = time.Millisecond
+
+	opts = append([]ekdsend.ClientOption{
+		ekdsend.WithHTTPClient(&http.Client{Transport: m}),
+		ekdsend.WithRetryPolicy(retry),
+	}, opts...)
+
+	client, err := ekdsend.New(mockAPIKey, opts...)
+	if err != nil {
+		panic(fmt.Sprintf("ekdsendtest: %v", err))
+	}
+
+	if u, err := url.Parse(client.Config().BaseURL); err == nil {
+		m.basePath = strings.TrimSuffix(u.Path, "/")
+	}
+	return client, m
+}
+
+// Enqueue adds replies for requests to method and path, e.g. "POST" and
+// "/emails". Each reply is served once, in order; the query string is not
+// matched. Requests without a remaining reply get a 404 with the code
+// "MOCK_NO_REPLY".
+func (m *Mock) Enqueue(method, path string, replies ...Reply) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := replyKey(method, path)
+	m.replies[key] = append(m.replies[key], replies...)
+}
+
+// Requests returns every request received so far, in order
+func (m *Mock) Requests() []Request {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Request(nil), m.requests...)
+}
+
+// LastRequest returns the most recent request, if any
+func (m *Mock) LastRequest() (Request, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.requests) == 0 {
+		return Request{}, false
+	}
+	return m.requests[len(m.requests)-1], true
+}
+
+// Pending returns the number of enqueued replies not yet served, e.g. to
+// check that every expected request was made
+func (m *Mock) Pending() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := 0
+	for _, queue := range m.replies {
+		n += len(queue)
+	}
+	return n
+}
+
+// Reset discards all enqueued replies and recorded requests
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.replies = map[string][]Reply{}
+	m.requests = nil
+}
+
+func replyKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// RoundTrip implements http.RoundTripper
+func (m *Mock) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+	}
+
+	path := req.URL.Path
+	if rest, ok := strings.CutPrefix(path, m.basePath); ok && strings.HasPrefix(rest, "/") {
+		path = rest
+	}
+
+	key := replyKey(req.Method, path)
+
+	m.mu.Lock()
+	m.requests = append(m.requests, Request{
+		Method: req.Method,
+		Path:   path,
+		Query:  req.URL.Query(),
+		Header: req.Header.Clone(),
+		Body:   body,
+	})
+	queue := m.replies[key]
+	var reply Reply
+	found := len(queue) > 0
+	if found {
+		reply = queue[0]
+		m.replies[key] = queue[1:]
+	}
+	m.mu.Unlock()
+
+	if !found {
+		reply = Error(http.StatusNotFound, "MOCK_NO_REPLY", fmt.Sprintf("no reply enqueued for %s", key))
+	}
+	if reply.Err != nil {
+		return nil, reply.Err
+	}
+
+	status := reply.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	header := reply.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(reply.Body))),
+		ContentLength: int64(len(reply.Body)),
+		Request:       req,
+	}, nil
+}
@@ -2,10 +2,14 @@ package ekdsend
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // EmailsAPI provides access to the Email API
@@ -14,6 +18,22 @@ type EmailsAPI struct {
 }
 
 // SendEmailParams are the parameters for sending an email
+//
+// ReplyToList takes precedence over ReplyTo: when ReplyToList is non-empty,
+// ReplyTo is ignored and the addresses are sent as a single RFC 5322
+// address list.
+//
+// AutoPlainText overrides the client's WithAutoPlainText setting for this
+// call; nil uses the client default.
+//
+// RequireTLS asks the API to deliver only over an encrypted connection
+// (RFC 8689 REQUIRETLS / MTA-STS enforcement). Recipients whose servers
+// cannot negotiate TLS will bounce instead of receiving the message in
+// plaintext, so only enable it for content that must not travel
+// unencrypted.
+//
+// IdempotencyKey is sent as the Idempotency-Key header; see
+// WithIdempotencyKey.
 type SendEmailParams struct {
 	From        string            `json:"from"`
 	To          []string          `json:"to"`
@@ -23,11 +43,76 @@ type SendEmailParams struct {
 	CC          []string          `json:"cc,omitempty"`
 	BCC         []string          `json:"bcc,omitempty"`
 	ReplyTo     string            `json:"reply_to,omitempty"`
+	ReplyToList []string          `json:"-"`
+	ReturnPath  string            `json:"return_path,omitempty"`
 	Attachments []Attachment      `json:"attachments,omitempty"`
 	Headers     map[string]string `json:"headers,omitempty"`
 	Tags        []string          `json:"tags,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
 	ScheduledAt string            `json:"scheduled_at,omitempty"`
+	RequireTLS  bool              `json:"require_tls,omitempty"`
+
+	AutoPlainText  *bool  `json:"-"`
+	IdempotencyKey string `json:"-"`
+}
+
+// MarshalJSON encodes the params, folding ReplyToList into reply_to
+func (p SendEmailParams) MarshalJSON() ([]byte, error) {
+	type params SendEmailParams
+	out := params(p)
+	out.ReplyTo = p.replyTo()
+	return json.Marshal(out)
+}
+
+// replyTo resolves ReplyToList and ReplyTo into a single address list
+func (p *SendEmailParams) replyTo() string {
+	if len(p.ReplyToList) > 0 {
+		return strings.Join(p.ReplyToList, ", ")
+	}
+	return p.ReplyTo
+}
+
+// maxEmailBatchSize is the largest number of emails accepted by the batch endpoint
+const maxEmailBatchSize = 100
+
+// recipients returns every To, CC and BCC address of the email
+func (p *SendEmailParams) recipients() []string {
+	recipients := make([]string, 0, len(p.To)+len(p.CC)+len(p.BCC))
+	recipients = append(recipients, p.To...)
+	recipients = append(recipients, p.CC...)
+	return append(recipients, p.BCC...)
+}
+
+// emailRecipients returns the recipients of every email
+func emailRecipients(emails []*SendEmailParams) []string {
+	var recipients []string
+	for _, params := range emails {
+		recipients = append(recipients, params.recipients()...)
+	}
+	return recipients
+}
+
+// withoutRecipients returns a copy of p without the blocked addresses and
+// the addresses that were removed
+func (p *SendEmailParams) withoutRecipients(blocked map[string]bool) (*SendEmailParams, []string) {
+	var removed []string
+	filter := func(addresses []string) []string {
+		var kept []string
+		for _, address := range addresses {
+			if blocked[normalizeAddress(address)] {
+				removed = append(removed, address)
+				continue
+			}
+			kept = append(kept, address)
+		}
+		return kept
+	}
+
+	out := *p
+	out.To = filter(p.To)
+	out.CC = filter(p.CC)
+	out.BCC = filter(p.BCC)
+	return &out, removed
 }
 
 // ListEmailsParams are the parameters for listing emails
@@ -38,34 +123,426 @@ type ListEmailsParams struct {
 	FromDate string
 	ToDate   string
 	Tags     []string
+	Metadata map[string]string
+
+	// Before and After are cursor tokens from PaginatedResponse.LastCursor
+	// and FirstCursor. When either is set, records are paged by creation
+	// time and ID instead of Offset.
+	Before string
+	After  string
+
+	// Cursor is a server-issued token from PaginatedResponse.NextCursor.
+	// When set it takes precedence over Offset, Before and After.
+	Cursor string
 }
 
 // Send sends an email
-func (e *EmailsAPI) Send(ctx context.Context, params *SendEmailParams) (*Email, error) {
-	var resp struct {
-		Data Email `json:"data"`
+func (e *EmailsAPI) Send(ctx context.Context, params *SendEmailParams, opts ...RequestOption) (*Email, error) {
+	if err := e.client.checkTestMode(); err != nil {
+		return nil, err
+	}
+	if err := e.client.checkAttachmentCount(len(params.Attachments)); err != nil {
+		return nil, err
+	}
+
+	params = e.prepare(params)
+	if err := e.check(params); err != nil {
+		return nil, err
+	}
+
+	if e.client.simulate {
+		email := e.client.simulateEmail(params)
+		e.client.sent("emails", params.recipients(), email.ID, string(email.Status), nil, nil)
+		return email, nil
+	}
+
+	if e.client.suppressionGuard {
+		if err := e.client.checkSuppressed(ctx, params.recipients()); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp Email
+	var meta ResponseMeta
+
+	err := e.client.Post(ctx, "/emails", params, envelope(&resp), withMeta(&meta, withParamsKey(params.IdempotencyKey, opts))...)
+	e.client.sent("emails", params.recipients(), resp.ID, string(resp.Status), &meta, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// SendThrottled sends params to each recipient individually, spreading the
+// sends over time so that at most ratePerMinute emails are scheduled per
+// minute. The first window starts at params.ScheduledAt, or immediately
+// when it is empty, and each following window is scheduled one minute
+// later. params.To, CC and BCC are ignored: each email goes to its
+// recipient only. It returns the IDs of the created emails; recipients the API
+// rejected are reported together in the returned error. With WithFailFast,
+// sending stops at the first rejected recipient. Recipients removed by
+// WithAutoSuppressionFilter are skipped without an error.
+func (e *EmailsAPI) SendThrottled(ctx context.Context, params *SendEmailParams, recipients []string, ratePerMinute int, opts ...BatchOption) ([]string, error) {
+	options := newBatchOptions(opts)
+
+	if ratePerMinute <= 0 {
+		return nil, errors.New("ratePerMinute must be positive")
+	}
+
+	start := e.client.clock.Now().UTC()
+	if params.ScheduledAt != "" {
+		t, err := time.Parse(time.RFC3339, params.ScheduledAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ScheduledAt: %w", err)
+		}
+		start = t.UTC()
+	}
+
+	var ids []string
+	var errs []error
+
+	for i := 0; i < len(recipients); i += maxEmailBatchSize {
+		end := i + maxEmailBatchSize
+		if end > len(recipients) {
+			end = len(recipients)
+		}
+
+		batch := make([]*SendEmailParams, 0, end-i)
+		for j, recipient := range recipients[i:end] {
+			p := *params
+			p.To = []string{recipient}
+			p.CC = nil
+			p.BCC = nil
+			p.ScheduledAt = ""
+			if window := (i + j) / ratePerMinute; window > 0 || params.ScheduledAt != "" {
+				p.ScheduledAt = start.Add(time.Duration(window) * time.Minute).Format(time.RFC3339)
+			}
+			batch = append(batch, &p)
+		}
+
+		results, err := e.batchSend(ctx, batch, options.chunk(i))
+		if err != nil {
+			return ids, errors.Join(append(errs, err)...)
+		}
+
+		for _, res := range results {
+			switch {
+			case res.Index < 0 || res.Index >= len(batch):
+				continue
+			case res.Error != nil && res.Error.Code == codeRecipientsSuppressed:
+				continue
+			case res.Error != nil:
+				errs = append(errs, fmt.Errorf("recipient %s: %w", batch[res.Index].To[0], res.Error.err()))
+			case res.Data != nil:
+				ids = append(ids, res.Data.ID)
+			}
+		}
+		if options.failFast && len(errs) > 0 {
+			break
+		}
+	}
+
+	return ids, errors.Join(errs...)
+}
+
+// BatchSend sends emails through the batch endpoint, up to 100 per request,
+// and reports the outcome of every item. Rejected items are returned in
+// Failed rather than as an error; an error is returned only when a request
+// as a whole fails, together with the results collected before it. Items
+// that fail local validation are reported in Failed with a
+// *ValidationError without being sent. With WithFailFast, no further
+// requests are made once an item has failed.
+func (e *EmailsAPI) BatchSend(ctx context.Context, emails []*SendEmailParams, opts ...BatchOption) (*BatchResult[Email], error) {
+	options := newBatchOptions(opts)
+	result := &BatchResult[Email]{}
+
+	for i, params := range emails {
+		if err := e.client.checkAttachmentCount(len(params.Attachments)); err != nil {
+			return result, fmt.Errorf("batch item %d: %w", i, err)
+		}
+	}
+
+	for i := 0; i < len(emails); i += maxEmailBatchSize {
+		end := i + maxEmailBatchSize
+		if end > len(emails) {
+			end = len(emails)
+		}
+
+		results, err := e.batchSend(ctx, emails[i:end], options.chunk(i))
+		if err != nil {
+			return result, err
+		}
+
+		result.add(results, i, end-i)
+		if options.failFast && len(result.Failed) > 0 {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// SendRaw sends a MIME message built by the caller, bypassing the
+// structured params. from and to are the envelope sender and recipients;
+// rawMIME must parse as an RFC 5322 message carrying From and Date headers.
+// The message is checked locally and a *ValidationError is returned before
+// anything is sent if it is malformed.
+func (e *EmailsAPI) SendRaw(ctx context.Context, from string, to []string, rawMIME []byte, opts ...RequestOption) (*Email, error) {
+	if err := e.client.checkTestMode(); err != nil {
+		return nil, err
+	}
+	if from == "" {
+		return nil, newValidationError("envelope sender is required", map[string]interface{}{"from": "required"})
+	}
+	if len(to) == 0 {
+		return nil, newValidationError("at least one envelope recipient is required", map[string]interface{}{"to": "required"})
+	}
+	if err := validateRawMIME(rawMIME); err != nil {
+		return nil, newValidationError(fmt.Sprintf("invalid MIME message: %v", err), map[string]interface{}{"raw": err.Error()})
+	}
+
+	if e.client.simulate {
+		email := e.client.simulateEmail(&SendEmailParams{From: from, To: to})
+		e.client.sent("emails", to, email.ID, string(email.Status), nil, nil)
+		return email, nil
+	}
+
+	if e.client.suppressionGuard {
+		if err := e.client.checkSuppressed(ctx, to); err != nil {
+			return nil, err
+		}
+	}
+
+	body := struct {
+		From string   `json:"from"`
+		To   []string `json:"to"`
+		Raw  string   `json:"raw"`
+	}{
+		From: from,
+		To:   to,
+		Raw:  base64.StdEncoding.EncodeToString(rawMIME),
+	}
+
+	var resp Email
+	var meta ResponseMeta
+
+	err := e.client.Post(ctx, "/emails/raw", body, envelope(&resp), withMeta(&meta, opts)...)
+	e.client.sent("emails", to, resp.ID, string(resp.Status), &meta, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// batchSend posts several emails to the batch endpoint in one request and
+// returns the per-item results reported by the API. With fail-fast the API
+// stops processing at the first failing item. Emails that fail local
+// validation are not sent and fail with a *ValidationError; with fail-fast
+// no email after them is sent either. When the suppression filter is
+// enabled, suppressed recipients are removed first and emails left
+// without a To recipient are not sent. Otherwise, with the suppression
+// guard, emails with any suppressed recipient fail with a
+// *SuppressedError and are not sent.
+func (e *EmailsAPI) batchSend(ctx context.Context, items []*SendEmailParams, options batchOptions) ([]batchItem[Email], error) {
+	if err := e.client.checkTestMode(); err != nil {
+		return nil, err
 	}
+	var results []batchItem[Email]
+	prepared := make([]*SendEmailParams, 0, len(items))
+	indexes := make([]int, 0, len(items))
 
-	err := e.client.Post(ctx, "/emails", params, &resp)
+	for i, params := range items {
+		if err := e.client.checkAttachmentCount(len(params.Attachments)); err != nil {
+			return nil, fmt.Errorf("batch item %d: %w", i, err)
+		}
+
+		params = e.prepare(params)
+		if err := e.check(params); err != nil {
+			results = append(results, batchItem[Email]{
+				Index: i,
+				Error: &batchItemError{Message: err.Error(), Code: "VALIDATION_ERROR", local: err},
+			})
+			if options.failFast {
+				break
+			}
+			continue
+		}
+		indexes = append(indexes, i)
+		prepared = append(prepared, params)
+	}
+
+	var blocked map[string]bool
+	if e.client.suppressionFilter || e.client.suppressionGuard {
+		var err error
+		if blocked, err = e.client.suppressedAmong(ctx, emailRecipients(prepared)); err != nil {
+			return nil, err
+		}
+	}
+
+	removed := make(map[int][]string)
+	sendIndexes := make([]int, 0, len(prepared))
+	sending := make([]*SendEmailParams, 0, len(prepared))
+
+	for j, params := range prepared {
+		i := indexes[j]
+		if blocked == nil {
+			sendIndexes = append(sendIndexes, i)
+			sending = append(sending, params)
+			continue
+		}
+
+		kept, dropped := params.withoutRecipients(blocked)
+		if !e.client.suppressionFilter {
+			if len(dropped) > 0 {
+				results = append(results, batchItem[Email]{
+					Index: i,
+					Error: &batchItemError{Message: "recipients are suppressed", local: &SuppressedError{Recipients: dropped}},
+				})
+				continue
+			}
+			sendIndexes = append(sendIndexes, i)
+			sending = append(sending, params)
+			continue
+		}
+
+		removed[i] = dropped
+		if len(kept.To) == 0 {
+			results = append(results, batchItem[Email]{
+				Index:      i,
+				Error:      &batchItemError{Message: "all recipients are suppressed", Code: codeRecipientsSuppressed},
+				Suppressed: dropped,
+			})
+			continue
+		}
+		sendIndexes = append(sendIndexes, i)
+		sending = append(sending, kept)
+	}
+
+	if len(sending) > 0 {
+		sent, err := e.postBatch(ctx, sending, options)
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range sent {
+			if res.Index < 0 || res.Index >= len(sendIndexes) {
+				continue
+			}
+			res.Index = sendIndexes[res.Index]
+			res.Suppressed = removed[res.Index]
+			results = append(results, res)
+		}
+	}
+
+	return results, nil
+}
+
+// postBatch sends prepared emails to the batch endpoint
+func (e *EmailsAPI) postBatch(ctx context.Context, emails []*SendEmailParams, options batchOptions) ([]batchItem[Email], error) {
+	body := struct {
+		Emails   []*SendEmailParams `json:"emails"`
+		FailFast bool               `json:"fail_fast,omitempty"`
+	}{
+		Emails:   emails,
+		FailFast: options.failFast,
+	}
+
+	if e.client.simulate {
+		results := make([]batchItem[Email], len(body.Emails))
+		for i, params := range body.Emails {
+			email := e.client.simulateEmail(params)
+			e.client.sent("emails", params.recipients(), email.ID, string(email.Status), nil, nil)
+			results[i] = batchItem[Email]{Index: i, Data: email}
+		}
+		return results, nil
+	}
+
+	var resp []batchItem[Email]
+	var meta ResponseMeta
+
+	err := e.client.Post(ctx, "/emails/batch", body, envelope(&resp), options.requestOptions(&meta)...)
 	if err != nil {
+		for _, params := range body.Emails {
+			e.client.sent("emails", params.recipients(), "", "", &meta, err)
+		}
 		return nil, err
 	}
 
-	return &resp.Data, nil
+	for _, res := range resp {
+		if res.Index < 0 || res.Index >= len(body.Emails) {
+			continue
+		}
+		recipients := body.Emails[res.Index].recipients()
+		switch {
+		case res.Error != nil:
+			res.Error.requestID = meta.RequestID
+			e.client.sent("emails", recipients, "", "", &meta, res.Error.err())
+		case res.Data != nil:
+			e.client.sent("emails", recipients, res.Data.ID, string(res.Data.Status), &meta, nil)
+		}
+	}
+
+	return resp, nil
+}
+
+// check normalizes the recipients of prepared params in place and
+// validates them, unless the client disabled either step
+func (e *EmailsAPI) check(params *SendEmailParams) error {
+	if !e.client.skipNormalization {
+		if err := NormalizeRecipients(params); err != nil {
+			return err
+		}
+	}
+	if !e.client.skipValidation {
+		return params.Validate()
+	}
+	return nil
+}
+
+// prepare applies client-side defaults to a copy of params
+func (e *EmailsAPI) prepare(params *SendEmailParams) *SendEmailParams {
+	p := *params
+
+	if p.From == "" && e.client.fromPool != nil {
+		p.From = e.client.fromPool.pick()
+	}
+	p.Metadata = e.client.withDefaultMetadata(p.Metadata)
+	p.Tags = e.client.withDefaultTags(p.Tags)
+
+	autoText := e.client.autoPlainText
+	if p.AutoPlainText != nil {
+		autoText = *p.AutoPlainText
+	}
+	if autoText {
+		p.GenerateText()
+	}
+
+	return &p
 }
 
 // Get retrieves an email by ID
-func (e *EmailsAPI) Get(ctx context.Context, emailID string) (*Email, error) {
-	var resp struct {
-		Data Email `json:"data"`
+func (e *EmailsAPI) Get(ctx context.Context, emailID string, opts ...RequestOption) (*Email, error) {
+	var resp Email
+
+	err := e.client.Get(ctx, fmt.Sprintf("/emails/%s", emailID), nil, envelope(&resp), opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	err := e.client.Get(ctx, fmt.Sprintf("/emails/%s", emailID), nil, &resp)
+	return &resp, nil
+}
+
+// GetLinks retrieves the tracked links of an email with their click counts
+func (e *EmailsAPI) GetLinks(ctx context.Context, emailID string) ([]TrackedLink, error) {
+	var resp []TrackedLink
+
+	err := e.client.Get(ctx, fmt.Sprintf("/emails/%s/links", emailID), nil, envelope(&resp))
 	if err != nil {
 		return nil, err
 	}
 
-	return &resp.Data, nil
+	return resp, nil
 }
 
 // List retrieves a paginated list of emails
@@ -76,7 +553,11 @@ func (e *EmailsAPI) List(ctx context.Context, params *ListEmailsParams) (*Pagina
 
 	query := url.Values{}
 	query.Set("limit", strconv.Itoa(params.Limit))
-	query.Set("offset", strconv.Itoa(params.Offset))
+	if params.Cursor != "" {
+		query.Set("cursor", params.Cursor)
+	} else if err := setPageQuery(query, params.Offset, params.Before, params.After); err != nil {
+		return nil, err
+	}
 
 	if params.Status != "" {
 		query.Set("status", params.Status)
@@ -87,9 +568,8 @@ func (e *EmailsAPI) List(ctx context.Context, params *ListEmailsParams) (*Pagina
 	if params.ToDate != "" {
 		query.Set("to_date", params.ToDate)
 	}
-	if len(params.Tags) > 0 {
-		query.Set("tags", strings.Join(params.Tags, ","))
-	}
+	e.client.setArrayQuery(query, "tags", params.Tags)
+	setMetadataQuery(query, params.Metadata)
 
 	var resp PaginatedResponse[Email]
 	err := e.client.Get(ctx, "/emails", query, &resp)
@@ -97,19 +577,102 @@ func (e *EmailsAPI) List(ctx context.Context, params *ListEmailsParams) (*Pagina
 		return nil, err
 	}
 
+	switch {
+	case resp.NextCursor != "":
+		cursor := resp.NextCursor
+		resp.next = func(ctx context.Context) (*PaginatedResponse[Email], error) {
+			next := *params
+			next.Cursor, next.Offset, next.Before, next.After = cursor, 0, "", ""
+			return e.List(ctx, &next)
+		}
+	case params.Cursor == "":
+		resp.bindNext(params.Before, params.After, func(ctx context.Context, offset int, before string) (*PaginatedResponse[Email], error) {
+			next := *params
+			next.Offset, next.Before = offset, before
+			return e.List(ctx, &next)
+		})
+	}
+
 	return &resp, nil
 }
 
 // Cancel cancels a scheduled email
 func (e *EmailsAPI) Cancel(ctx context.Context, emailID string) (*Email, error) {
-	var resp struct {
-		Data Email `json:"data"`
+	var resp Email
+
+	err := e.client.Delete(ctx, fmt.Sprintf("/emails/%s", emailID), envelope(&resp))
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// UpdateEmailParams are the changes to a scheduled email. Empty fields are
+// left unchanged.
+type UpdateEmailParams struct {
+	Subject string `json:"subject,omitempty"`
+	HTML    string `json:"html,omitempty"`
+	Text    string `json:"text,omitempty"`
+
+	// ScheduledAt reschedules the email; it is sent as UTC RFC 3339 and
+	// must be in the future
+	ScheduledAt *time.Time `json:"-"`
+}
+
+// MarshalJSON encodes the params with ScheduledAt in UTC
+func (p UpdateEmailParams) MarshalJSON() ([]byte, error) {
+	type plain UpdateEmailParams
+	out := struct {
+		plain
+		ScheduledAt string `json:"scheduled_at,omitempty"`
+	}{plain: plain(p)}
+	if p.ScheduledAt != nil {
+		out.ScheduledAt = p.ScheduledAt.UTC().Format(time.RFC3339)
 	}
+	return json.Marshal(out)
+}
+
+// Update changes a scheduled email before it is sent. Once the email has
+// left the scheduled state the API answers 409 and Update returns an error
+// wrapping the *ConflictError.
+func (e *EmailsAPI) Update(ctx context.Context, emailID string, params *UpdateEmailParams, opts ...RequestOption) (*Email, error) {
+	if params.Subject == "" && params.HTML == "" && params.Text == "" && params.ScheduledAt == nil {
+		return nil, newValidationError("nothing to update", map[string]interface{}{"params": "at least one field is required"})
+	}
+	if params.ScheduledAt != nil {
+		if _, err := e.client.scheduledAt(*params.ScheduledAt); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp Email
 
-	err := e.client.Delete(ctx, fmt.Sprintf("/emails/%s", emailID), &resp)
+	err := e.client.Patch(ctx, fmt.Sprintf("/emails/%s", emailID), params, envelope(&resp), opts...)
+	if IsConflictError(err) {
+		return nil, fmt.Errorf("email %s is no longer scheduled and cannot be updated: %w", emailID, err)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &resp.Data, nil
+	return &resp, nil
+}
+
+// CancelByMetadata cancels every scheduled email whose metadata has key
+// set to value and returns how many were canceled. Matching emails are
+// collected before any is canceled; emails sent or canceled in the
+// meantime are skipped. An empty key returns a *ValidationError.
+func (e *EmailsAPI) CancelByMetadata(ctx context.Context, key, value string) (int, error) {
+	return cancelByMetadata(ctx, key,
+		e.ListAll(ctx, &ListEmailsParams{
+			Limit:    100,
+			Status:   string(EmailStatusScheduled),
+			Metadata: map[string]string{key: value},
+		}),
+		func(item *Email) string { return item.ID },
+		func(ctx context.Context, id string) error {
+			_, err := e.Cancel(ctx, id)
+			return err
+		})
 }
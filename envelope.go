@@ -0,0 +1,40 @@
+package ekdsend
+
+import "encoding/json"
+
+// DefaultEnvelopeKey is the field API responses wrap their payload in
+const DefaultEnvelopeKey = "data"
+
+// WithEnvelopeKey sets the field that wraps response payloads, e.g.
+// {"result": {...}} for key "result". Responses without the field are
+// decoded from the top-level object, so bare responses work with any key;
+// an empty key always decodes the top-level object.
+func WithEnvelopeKey(key string) ClientOption {
+	return func(c *Client) {
+		c.envelopeKey = key
+	}
+}
+
+// enveloped marks a result whose payload is wrapped in the envelope key
+type enveloped struct {
+	v interface{}
+}
+
+// envelope wraps v so the response is unwrapped before decoding into it
+func envelope(v interface{}) interface{} {
+	return &enveloped{v: v}
+}
+
+// unwrap decodes body into v, reading the envelope field when the response
+// has one and the top-level object otherwise
+func (c *Client) unwrap(body []byte, v interface{}) error {
+	if c.envelopeKey != "" {
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(body, &fields) == nil {
+			if payload, ok := fields[c.envelopeKey]; ok {
+				return json.Unmarshal(payload, v)
+			}
+		}
+	}
+	return json.Unmarshal(body, v)
+}
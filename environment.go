@@ -0,0 +1,81 @@
+package ekdsend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Deployment environments accepted by WithEnforceEnvironment
+const (
+	EnvironmentProduction  = "production"
+	EnvironmentStaging     = "staging"
+	EnvironmentDevelopment = "dev"
+)
+
+// environmentKeyPrefixes maps each environment to the API key prefix it
+// requires
+var environmentKeyPrefixes = map[string]string{
+	EnvironmentProduction:  "ek_live_",
+	EnvironmentStaging:     "ek_test_",
+	EnvironmentDevelopment: "ek_test_",
+	"development":          "ek_test_",
+}
+
+// WithEnforceEnvironment makes New fail unless the API key matches env:
+// "production" requires a live key (ek_live_), "staging" and "dev" a test
+// key (ek_test_). It guards against shipping a test key to production, or
+// sending real messages from CI. An unknown env is also an error.
+func WithEnforceEnvironment(env string) ClientOption {
+	return func(c *Client) {
+		prefix, ok := environmentKeyPrefixes[strings.ToLower(strings.TrimSpace(env))]
+		if !ok {
+			c.setOptErr(fmt.Errorf("unknown environment %q", env))
+			return
+		}
+		if !strings.HasPrefix(c.apiKey, prefix) {
+			c.setOptErr(fmt.Errorf("API key %s does not match environment %q: expected a key starting with %q", maskAPIKey(c.apiKey), env, prefix))
+		}
+	}
+}
+
+// API key modes reported by Client.Mode
+const (
+	ModeLive = "live"
+	ModeTest = "test"
+)
+
+// Mode returns the mode of the client's API key: ModeTest for ek_test_
+// keys, ModeLive for ek_live_ keys
+func (c *Client) Mode() string {
+	if c.IsTestMode() {
+		return ModeTest
+	}
+	return ModeLive
+}
+
+// IsTestMode reports whether the client uses a test (ek_test_) key, whose
+// messages are never delivered
+func (c *Client) IsTestMode() bool {
+	return strings.HasPrefix(c.apiKey, "ek_test_")
+}
+
+// WithRequireTestMode makes every email, SMS and call send return a
+// *LiveModeError without sending when the client has a live (ek_live_)
+// key, so a misconfigured staging or CI environment cannot send real
+// messages. Unlike WithEnforceEnvironment, New still succeeds and read
+// operations keep working, so the mistake surfaces at the first send
+// rather than at startup.
+func WithRequireTestMode() ClientOption {
+	return func(c *Client) {
+		c.requireTestMode = true
+	}
+}
+
+// checkTestMode returns a *LiveModeError when WithRequireTestMode is set
+// and the key is live
+func (c *Client) checkTestMode() error {
+	if c.requireTestMode && !c.IsTestMode() {
+		return &LiveModeError{APIKey: maskAPIKey(c.apiKey)}
+	}
+	return nil
+}
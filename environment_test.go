@@ -0,0 +1,74 @@
+package ekdsend
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithRequireTestModeRefusesLiveSends(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		writeEmail(w, "em_1")
+	}))
+	defer srv.Close()
+
+	client, err := New("ek_live_1234567890", WithBaseURL(srv.URL), WithRequireTestMode())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx := context.Background()
+
+	sends := map[string]func() error{
+		"Emails.Send": func() error {
+			_, err := client.Emails.Send(ctx, testEmailParams())
+			return err
+		},
+		"Emails.Schedule": func() error {
+			_, err := client.Emails.Schedule(ctx, testEmailParams(), time.Now().Add(time.Hour))
+			return err
+		},
+		"Emails.BatchSend": func() error {
+			_, err := client.Emails.BatchSend(ctx, []*SendEmailParams{testEmailParams()})
+			return err
+		},
+		"SMS.Send": func() error {
+			_, err := client.SMS.Send(ctx, &SendSMSParams{To: "+14155550100", Message: "Hi"})
+			return err
+		},
+		"Calls.Create": func() error {
+			_, err := client.Calls.Create(ctx, &CreateCallParams{To: "+14155550100", From: "+14155550101", TTSMessage: "Hi"})
+			return err
+		},
+	}
+	for name, send := range sends {
+		err := send()
+		var liveErr *LiveModeError
+		if !errors.Is(err, ErrLiveMode) || !errors.As(err, &liveErr) {
+			t.Errorf("%s error = %v, want a *LiveModeError", name, err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("API received %d requests, want 0", requests)
+	}
+
+	if _, err := client.Emails.Get(ctx, "em_1"); err != nil {
+		t.Errorf("Get with a live key: %v", err)
+	}
+}
+
+func TestWithRequireTestModeAllowsTestKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeEmail(w, "em_1")
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL, WithRequireTestMode())
+	if _, err := client.Emails.Send(context.Background(), testEmailParams()); err != nil {
+		t.Errorf("Send with a test key: %v", err)
+	}
+}
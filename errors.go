@@ -1,6 +1,30 @@
 package ekdsend
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// Sentinel errors matched by the error types' Is methods, so that
+// errors.Is(err, ErrRateLimited) holds for a *RateLimitError however deeply
+// it is wrapped
+var (
+	ErrAuthentication = errors.New("ekdsend: authentication failed")
+	ErrValidation     = errors.New("ekdsend: validation failed")
+	ErrRateLimited    = errors.New("ekdsend: rate limited")
+	ErrNotFound       = errors.New("ekdsend: not found")
+	ErrPermission     = errors.New("ekdsend: permission denied")
+	ErrConflict       = errors.New("ekdsend: conflict")
+	ErrServer         = errors.New("ekdsend: server error")
+	ErrSuppressed     = errors.New("ekdsend: recipient suppressed")
+	ErrLiveMode       = errors.New("ekdsend: live key used where test mode is required")
+	ErrNetwork        = errors.New("ekdsend: network error")
+	ErrTimeout        = errors.New("ekdsend: request timed out")
+)
 
 // EKDSendError is the base error type for API errors
 type EKDSendError struct {
@@ -8,6 +32,12 @@ type EKDSendError struct {
 	StatusCode int    `json:"status_code"`
 	Code       string `json:"code"`
 	RequestID  string `json:"request_id"`
+
+	// RawBody and ContentType are set when the error response was not
+	// JSON, such as a proxy's HTML error page. RawBody holds at most the
+	// first 1 KiB of the body.
+	RawBody     string `json:"raw_body,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
 }
 
 func (e *EKDSendError) Error() string {
@@ -24,43 +54,300 @@ type AuthenticationError struct {
 	EKDSendError
 }
 
-// ValidationError is returned when request validation fails (400)
+// Is reports whether target is ErrAuthentication
+func (e *AuthenticationError) Is(target error) bool {
+	return target == ErrAuthentication
+}
+
+// ValidationError is returned when request validation fails (400).
+// Errors detected locally, before any request is sent, have a zero
+// StatusCode.
 type ValidationError struct {
 	EKDSendError
 	Errors map[string]interface{} `json:"errors"`
 }
 
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// FieldError is a validation failure of a single field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// FieldErrors returns the entries of Errors as FieldErrors, sorted by
+// field. A field's value may be a message, an object with "message" and
+// "code", or a list of either; each message in a list becomes its own
+// FieldError. Other values are formatted as the message.
+func (e *ValidationError) FieldErrors() []FieldError {
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var out []FieldError
+	for _, field := range fields {
+		out = appendFieldErrors(out, field, e.Errors[field])
+	}
+	return out
+}
+
+// FieldError returns the first error reported for the named field
+func (e *ValidationError) FieldError(name string) (FieldError, bool) {
+	value, ok := e.Errors[name]
+	if !ok {
+		return FieldError{}, false
+	}
+	errs := appendFieldErrors(nil, name, value)
+	if len(errs) == 0 {
+		return FieldError{}, false
+	}
+	return errs[0], true
+}
+
+// appendFieldErrors appends the errors described by value to out
+func appendFieldErrors(out []FieldError, field string, value interface{}) []FieldError {
+	switch v := value.(type) {
+	case nil:
+		return out
+	case string:
+		return append(out, FieldError{Field: field, Message: v})
+	case []string:
+		for _, message := range v {
+			out = append(out, FieldError{Field: field, Message: message})
+		}
+		return out
+	case []interface{}:
+		for _, item := range v {
+			out = appendFieldErrors(out, field, item)
+		}
+		return out
+	case map[string]interface{}:
+		fe := FieldError{Field: field}
+		if message, ok := v["message"].(string); ok {
+			fe.Message = message
+		}
+		if code, ok := v["code"].(string); ok {
+			fe.Code = code
+		}
+		if fe.Message == "" {
+			fe.Message = fe.Code
+		}
+		return append(out, fe)
+	default:
+		return append(out, FieldError{Field: field, Message: fmt.Sprint(v)})
+	}
+}
+
+// newValidationError builds a ValidationError for a client-side check
+func newValidationError(message string, errs map[string]interface{}) *ValidationError {
+	return &ValidationError{
+		EKDSendError: EKDSendError{
+			Message: message,
+			Code:    "VALIDATION_ERROR",
+		},
+		Errors: errs,
+	}
+}
+
 // RateLimitError is returned when rate limit is exceeded (429)
 type RateLimitError struct {
 	EKDSendError
 	RetryAfter int `json:"retry_after"`
 }
 
+// Is reports whether target is ErrRateLimited
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
 // NotFoundError is returned when resource is not found (404)
 type NotFoundError struct {
 	EKDSendError
 }
 
-// IsAuthenticationError checks if the error is an authentication error
+// Is reports whether target is ErrNotFound
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// PermissionError is returned when the API key may not perform the
+// request (403)
+type PermissionError struct {
+	EKDSendError
+}
+
+// Is reports whether target is ErrPermission
+func (e *PermissionError) Is(target error) bool {
+	return target == ErrPermission
+}
+
+// ConflictError is returned when a request conflicts with the current state
+// of a resource (409), such as an idempotency key reused with a different
+// body
+type ConflictError struct {
+	EKDSendError
+}
+
+// Is reports whether target is ErrConflict
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// ServerError is returned when the API fails to handle a request (5xx)
+type ServerError struct {
+	EKDSendError
+}
+
+// Is reports whether target is ErrServer
+func (e *ServerError) Is(target error) bool {
+	return target == ErrServer
+}
+
+// SuppressedError is returned by the email send methods with
+// WithSuppressionGuard when recipients are on the suppression list. Nothing
+// is sent.
+type SuppressedError struct {
+	Recipients []string
+}
+
+func (e *SuppressedError) Error() string {
+	return fmt.Sprintf("EKDSend: recipients suppressed: %s", strings.Join(e.Recipients, ", "))
+}
+
+// Is reports whether target is ErrSuppressed
+func (e *SuppressedError) Is(target error) bool {
+	return target == ErrSuppressed
+}
+
+// LiveModeError is returned by send methods of a client created with
+// WithRequireTestMode and a live API key. Nothing is sent.
+type LiveModeError struct {
+	// APIKey is the masked live key
+	APIKey string
+}
+
+func (e *LiveModeError) Error() string {
+	return fmt.Sprintf("EKDSend: API key %s is a live key, but test mode is required", e.APIKey)
+}
+
+// Is reports whether target is ErrLiveMode
+func (e *LiveModeError) Is(target error) bool {
+	return target == ErrLiveMode
+}
+
+// NetworkError is returned when a request fails at the transport level
+// (DNS, connection, TLS) before the API produced a response
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("EKDSend network error: %v", e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrNetwork
+func (e *NetworkError) Is(target error) bool {
+	return target == ErrNetwork
+}
+
+// Temporary reports whether the failure is likely transient, such as a
+// timeout, a temporary DNS failure, or a refused or reset connection
+func (e *NetworkError) Temporary() bool {
+	var dnsErr *net.DNSError
+	if errors.As(e.Err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	var netErr net.Error
+	if errors.As(e.Err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(e.Err, syscall.ECONNREFUSED) ||
+		errors.Is(e.Err, syscall.ECONNRESET) ||
+		errors.Is(e.Err, syscall.ECONNABORTED)
+}
+
+// TimeoutError is returned when a request's context deadline expires.
+// Unlike a cancellation (context.Canceled), a timeout is usually safe to
+// retry.
+type TimeoutError struct {
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("EKDSend request timed out: %v", e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrTimeout
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// IsAuthenticationError checks if err is, or wraps, an authentication error
 func IsAuthenticationError(err error) bool {
-	_, ok := err.(*AuthenticationError)
-	return ok
+	var target *AuthenticationError
+	return errors.As(err, &target)
 }
 
-// IsValidationError checks if the error is a validation error
+// IsValidationError checks if err is, or wraps, a validation error
 func IsValidationError(err error) bool {
-	_, ok := err.(*ValidationError)
-	return ok
+	var target *ValidationError
+	return errors.As(err, &target)
 }
 
-// IsRateLimitError checks if the error is a rate limit error
+// IsRateLimitError checks if err is, or wraps, a rate limit error
 func IsRateLimitError(err error) bool {
-	_, ok := err.(*RateLimitError)
-	return ok
+	var target *RateLimitError
+	return errors.As(err, &target)
 }
 
-// IsNotFoundError checks if the error is a not found error
+// IsNotFoundError checks if err is, or wraps, a not found error
 func IsNotFoundError(err error) bool {
-	_, ok := err.(*NotFoundError)
-	return ok
+	var target *NotFoundError
+	return errors.As(err, &target)
+}
+
+// IsPermissionError checks if err is, or wraps, a permission error
+func IsPermissionError(err error) bool {
+	var target *PermissionError
+	return errors.As(err, &target)
+}
+
+// IsConflictError checks if err is, or wraps, a conflict error
+func IsConflictError(err error) bool {
+	var target *ConflictError
+	return errors.As(err, &target)
+}
+
+// IsServerError checks if err is, or wraps, a server error
+func IsServerError(err error) bool {
+	var target *ServerError
+	return errors.As(err, &target)
+}
+
+// IsNetworkError checks if err is, or wraps, a transport-level network error
+func IsNetworkError(err error) bool {
+	var target *NetworkError
+	return errors.As(err, &target)
+}
+
+// IsTimeoutError checks if err is, or wraps, a request timeout
+func IsTimeoutError(err error) bool {
+	var target *TimeoutError
+	return errors.As(err, &target)
 }
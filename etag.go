@@ -0,0 +1,122 @@
+package ekdsend
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+)
+
+// DefaultETagCacheSize is the number of responses kept by WithETagCache
+// when no size is given
+const DefaultETagCacheSize = 1000
+
+// WithETagCache caches GET responses that carry an ETag and revalidates
+// them with If-None-Match on later requests for the same URL. A 304 Not
+// Modified answer is served from the cache, which saves bandwidth when
+// polling resources that rarely change. size bounds the number of cached
+// responses, evicting the least recently used; zero or a negative size
+// uses DefaultETagCacheSize. A 304 that cannot be answered from the cache
+// is returned as an *EKDSendError with code "NOT_MODIFIED" rather than an
+// empty result.
+func WithETagCache(size int) ClientOption {
+	return func(c *Client) {
+		if size <= 0 {
+			size = DefaultETagCacheSize
+		}
+		c.etags = &etagCache{
+			size:    size,
+			entries: make(map[string]*list.Element),
+			order:   list.New(),
+		}
+	}
+}
+
+// etagCache is a size-bounded LRU of GET responses keyed by URL
+type etagCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type etagEntry struct {
+	url  string
+	etag string
+	body []byte
+}
+
+// get returns the cached response for url
+func (e *etagCache) get(url string) (*etagEntry, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	elem, ok := e.entries[url]
+	if !ok {
+		return nil, false
+	}
+	e.order.MoveToFront(elem)
+	return elem.Value.(*etagEntry), true
+}
+
+// put stores a response, evicting the least recently used one when full
+func (e *etagCache) put(entry *etagEntry) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if elem, ok := e.entries[entry.url]; ok {
+		elem.Value = entry
+		e.order.MoveToFront(elem)
+		return
+	}
+
+	e.entries[entry.url] = e.order.PushFront(entry)
+	for e.order.Len() > e.size {
+		oldest := e.order.Back()
+		e.order.Remove(oldest)
+		delete(e.entries, oldest.Value.(*etagEntry).url)
+	}
+}
+
+// revalidate prepares a conditional GET for req and returns the cached
+// entry it depends on, if any
+func (e *etagCache) revalidate(req *http.Request) *etagEntry {
+	if e == nil || req.Method != http.MethodGet {
+		return nil
+	}
+
+	entry, ok := e.get(req.URL.String())
+	if !ok {
+		return nil
+	}
+	req.Header.Set("If-None-Match", entry.etag)
+	return entry
+}
+
+// resolve replaces a 304 answer to a conditional GET with the cached
+// response and caches fresh responses that carry an ETag
+func (e *etagCache) resolve(req *http.Request, cached *etagEntry, resp *response) *response {
+	if e == nil || req.Method != http.MethodGet {
+		return resp
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return &response{
+			StatusCode: http.StatusOK,
+			Header:     resp.Header,
+			Body:       cached.body,
+		}
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" && resp.StatusCode == http.StatusOK {
+		e.put(&etagEntry{url: req.URL.String(), etag: etag, body: resp.Body})
+	}
+	return resp
+}
+
+// etagCacheSize returns the cache capacity, or zero when caching is off
+func (c *Client) etagCacheSize() int {
+	if c.etags == nil {
+		return 0
+	}
+	return c.etags.size
+}
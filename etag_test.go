@@ -0,0 +1,70 @@
+package ekdsend
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestETagCacheServesNotModified(t *testing.T) {
+	var conditional []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		writeEmail(w, "em_1")
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL, WithETagCache(10))
+	for i := 0; i < 2; i++ {
+		email, err := client.Emails.Get(context.Background(), "em_1")
+		if err != nil {
+			t.Fatalf("Get %d: %v", i+1, err)
+		}
+		if email.ID != "em_1" {
+			t.Errorf("Get %d ID = %q, want em_1", i+1, email.ID)
+		}
+	}
+	if want := []string{"", `"v1"`}; len(conditional) != 2 || conditional[0] != want[0] || conditional[1] != want[1] {
+		t.Errorf("If-None-Match headers = %q, want %q", conditional, want)
+	}
+}
+
+func TestNotModifiedWithoutCachedResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL, WithETagCache(10))
+	email, err := client.Emails.Get(context.Background(), "em_1",
+		WithRequestHeaders(map[string]string{"If-None-Match": `"stale"`}))
+
+	var apiErr *EKDSendError
+	if !errors.As(err, &apiErr) || apiErr.Code != "NOT_MODIFIED" {
+		t.Fatalf("Get = %+v, %v; want NOT_MODIFIED error", email, err)
+	}
+}
+
+func TestETagCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	var c Client
+	WithETagCache(2)(&c)
+	cache := c.etags
+
+	cache.put(&etagEntry{url: "a", etag: "1"})
+	cache.put(&etagEntry{url: "b", etag: "1"})
+	cache.get("a")
+	cache.put(&etagEntry{url: "c", etag: "1"})
+
+	for url, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := cache.get(url); ok != want {
+			t.Errorf("cached %q = %t, want %t", url, ok, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package ekdsend
+
+import (
+	"fmt"
+	"math/rand"
+	"net/mail"
+	"sync/atomic"
+)
+
+// FromPoolStrategy selects how WithFromPool picks an address
+type FromPoolStrategy int
+
+const (
+	// FromPoolRoundRobin cycles through the pool in order
+	FromPoolRoundRobin FromPoolStrategy = iota
+	// FromPoolRandom picks a random address for each email
+	FromPoolRandom
+)
+
+// WithFromPool spreads sending volume across several verified addresses:
+// emails sent without a From get one picked from addresses using strategy.
+// Every address must parse as an RFC 5322 address, otherwise New returns
+// an error.
+func WithFromPool(addresses []string, strategy FromPoolStrategy) ClientOption {
+	return func(c *Client) {
+		if len(addresses) == 0 {
+			c.setOptErr(fmt.Errorf("from pool must contain at least one address"))
+			return
+		}
+		for _, address := range addresses {
+			if _, err := mail.ParseAddress(address); err != nil {
+				c.setOptErr(fmt.Errorf("invalid from pool address %q: %w", address, err))
+				return
+			}
+		}
+		if strategy != FromPoolRoundRobin && strategy != FromPoolRandom {
+			c.setOptErr(fmt.Errorf("unknown from pool strategy %d", strategy))
+			return
+		}
+
+		c.fromPool = &fromPool{
+			addresses: append([]string(nil), addresses...),
+			strategy:  strategy,
+		}
+	}
+}
+
+// fromPool hands out From addresses; it is safe for concurrent use
+type fromPool struct {
+	addresses []string
+	strategy  FromPoolStrategy
+	next      atomic.Uint64
+}
+
+func (p *fromPool) pick() string {
+	if p.strategy == FromPoolRandom {
+		return p.addresses[rand.Intn(len(p.addresses))]
+	}
+	n := p.next.Add(1) - 1
+	return p.addresses[n%uint64(len(p.addresses))]
+}
+
+// fromPoolAddresses returns a copy of the pool, or nil without one
+func (c *Client) fromPoolAddresses() []string {
+	if c.fromPool == nil {
+		return nil
+	}
+	return append([]string(nil), c.fromPool.addresses...)
+}
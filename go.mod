@@ -1,7 +1,8 @@
 module github.com/ekddigital/ekdsend-go
 
-go 1.21
+go 1.23
 
 require (
+golang.org/x/net v0.30.0
 golang.org/x/time v0.5.0
 )
\ No newline at end of file
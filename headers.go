@@ -0,0 +1,69 @@
+package ekdsend
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// protectedHeaders are set by the SDK itself and cannot be replaced with
+// WithDefaultHeaders or WithRequestHeaders
+var protectedHeaders = map[string]bool{
+	"Authorization":    true,
+	"Content-Type":     true,
+	"Content-Length":   true,
+	"Content-Encoding": true,
+	"Accept-Encoding":  true,
+	"Host":             true,
+	"User-Agent":       true,
+	"Idempotency-Key":  true,
+}
+
+// WithDefaultHeaders sends headers, such as a tenant or routing header,
+// with every request. Headers the SDK manages itself (Authorization,
+// Content-Type, Content-Length, Content-Encoding, Accept-Encoding, Host,
+// User-Agent and Idempotency-Key) cannot be set; New returns an error if
+// one is given. Use WithUserAgentSuffix and WithIdempotencyKey instead.
+func WithDefaultHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) {
+		for name, value := range headers {
+			name = http.CanonicalHeaderKey(name)
+			if protectedHeaders[name] {
+				c.setOptErr(fmt.Errorf("header %q is managed by the client and cannot be overridden", name))
+				return
+			}
+			if c.defaultHeaders == nil {
+				c.defaultHeaders = http.Header{}
+			}
+			c.defaultHeaders.Set(name, value)
+		}
+	}
+}
+
+// WithRequestHeaders sends headers with this call, e.g. an
+// X-Correlation-ID propagated from an upstream request. They take
+// precedence over WithDefaultHeaders. Headers managed by the SDK (see
+// WithDefaultHeaders) are ignored.
+func WithRequestHeaders(headers map[string]string) RequestOption {
+	return func(rc *requestConfig) {
+		if rc.headers == nil {
+			rc.headers = http.Header{}
+		}
+		for name, value := range headers {
+			rc.headers.Set(name, value)
+		}
+	}
+}
+
+// applyHeaders sets the default and per-call headers on req, skipping
+// protected ones
+func (c *Client) applyHeaders(req *http.Request, rc requestConfig) {
+	for _, headers := range []http.Header{c.defaultHeaders, rc.headers} {
+		for name, values := range headers {
+			if protectedHeaders[name] {
+				c.debugf("Ignoring protected header %s", name)
+				continue
+			}
+			req.Header[name] = append([]string(nil), values...)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package ekdsend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWithRequestHeadersCannotOverrideProtected(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		io.Copy(io.Discard, r.Body)
+		writeEmail(w, "em_1")
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL, WithDefaultHeaders(map[string]string{
+		"X-Tenant":  "default",
+		"X-Routing": "eu",
+	}))
+	params := testEmailParams()
+	params.IdempotencyKey = "key-1"
+
+	_, err := client.Emails.Send(context.Background(), params, WithRequestHeaders(map[string]string{
+		"authorization":   "Bearer ek_live_stolen",
+		"Idempotency-Key": "other-key",
+		"Content-Type":    "text/plain",
+		"X-Tenant":        "acme",
+	}))
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	want := map[string]string{
+		"Authorization":   "Bearer " + testAPIKey,
+		"Idempotency-Key": "key-1",
+		"Content-Type":    "application/json",
+		"X-Tenant":        "acme",
+		"X-Routing":       "eu",
+	}
+	for name, value := range want {
+		if got.Get(name) != value {
+			t.Errorf("%s = %q, want %q", name, got.Get(name), value)
+		}
+		if n := len(got.Values(name)); n != 1 {
+			t.Errorf("%s sent %d times, want once", name, n)
+		}
+	}
+}
+
+func TestWithDefaultHeadersRejectsProtected(t *testing.T) {
+	for _, name := range []string{"Authorization", "idempotency-key", "Content-Type"} {
+		_, err := New(testAPIKey, WithDefaultHeaders(map[string]string{name: "x"}))
+		if err == nil {
+			t.Errorf("New accepted default header %q", name)
+		}
+	}
+}
+
+func TestConfigDefaultHeaderNames(t *testing.T) {
+	client := newTestClient(t, "http://api.ekdsend.test", WithDefaultHeaders(map[string]string{
+		"x-tenant":  "acme",
+		"X-Api-Tag": "secret-value",
+	}))
+
+	got := client.Config().DefaultHeaders
+	if want := []string{"X-Api-Tag", "X-Tenant"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Config().DefaultHeaders = %v, want %v", got, want)
+	}
+}
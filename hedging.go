@@ -0,0 +1,67 @@
+package ekdsend
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// WithHedging sends a second, identical request when a GET has not been
+// answered within delay, and uses whichever response arrives first; the
+// slower request is cancelled. The hedged request waits for the rate
+// limiter like any other. Only GET requests are hedged, since they are
+// safe to issue twice.
+func WithHedging(delay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.hedgeDelay = delay
+	}
+}
+
+// hedgedAttempt runs attempt, starting a second copy of req if the first
+// has not completed after the hedge delay. It returns the first response
+// received, or the first error when both copies fail.
+func (c *Client) hedgedAttempt(ctx context.Context, hc *http.Client, req *http.Request) (*response, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		resp *response
+		err  error
+	}
+	results := make(chan result, 2)
+	run := func() {
+		resp, err := c.attempt(ctx, hc, req)
+		results <- result{resp, err}
+	}
+
+	go run()
+	pending := 1
+	hedge := c.clock.After(c.hedgeDelay)
+
+	var firstErr error
+	for pending > 0 {
+		select {
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				return res.resp, nil
+			}
+			if firstErr == nil {
+				firstErr = res.err
+			}
+		case <-hedge:
+			hedge = nil
+			pending++
+			go func() {
+				if err := c.waitRateLimit(ctx); err != nil {
+					results <- result{err: err}
+					return
+				}
+				c.debugf("Hedging %s %s", req.Method, req.URL.Path)
+				run()
+			}()
+		}
+	}
+
+	return nil, firstErr
+}
@@ -0,0 +1,61 @@
+package ekdsend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedgingCancelsSlowerRequest(t *testing.T) {
+	var requests atomic.Int32
+	slowCancelled := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			// The first request hangs until the hedge wins and cancels it
+			<-r.Context().Done()
+			close(slowCancelled)
+			return
+		}
+		writeEmail(w, "em_1")
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL, WithHedging(10*time.Millisecond))
+	email, err := client.Emails.Get(context.Background(), "em_1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if email.ID != "em_1" {
+		t.Errorf("ID = %q, want em_1", email.ID)
+	}
+
+	select {
+	case <-slowCancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("slower request was not cancelled")
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server received %d requests, want 2", n)
+	}
+}
+
+func TestHedgingSkipsFastResponses(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		writeEmail(w, "em_1")
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL, WithHedging(time.Minute))
+	if _, err := client.Emails.Get(context.Background(), "em_1"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server received %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,74 @@
+package ekdsend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// keyRecorder serves emails and batches, recording the Idempotency-Key
+// of each request by method and path
+type keyRecorder struct {
+	mu   sync.Mutex
+	keys map[string][]string
+}
+
+func newKeyRecorder(t *testing.T) (*httptest.Server, *keyRecorder) {
+	t.Helper()
+
+	rec := &keyRecorder{keys: make(map[string][]string)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		route := r.Method + " " + r.URL.Path
+		rec.keys[route] = append(rec.keys[route], r.Header.Get("Idempotency-Key"))
+		rec.mu.Unlock()
+
+		if r.URL.Path == "/emails/batch" {
+			writeBatch(w, r)
+			return
+		}
+		io.Copy(io.Discard, r.Body)
+		writeEmail(w, "em_1")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, rec
+}
+
+func TestBatchIdempotencyKey(t *testing.T) {
+	srv, rec := newKeyRecorder(t)
+	client := newTestClient(t, srv.URL)
+
+	emails := make([]*SendEmailParams, 150)
+	for i := range emails {
+		emails[i] = testEmailParams()
+	}
+	if _, err := client.Emails.BatchSend(context.Background(), emails, WithBatchIdempotencyKey("import-42")); err != nil {
+		t.Fatalf("BatchSend: %v", err)
+	}
+
+	got := rec.keys["POST /emails/batch"]
+	if len(got) != 2 || got[0] != "import-42-0" || got[1] != "import-42-100" {
+		t.Errorf("batch Idempotency-Keys = %q, want [import-42-0 import-42-100]", got)
+	}
+}
+
+func TestUpdateIdempotencyKey(t *testing.T) {
+	srv, rec := newKeyRecorder(t)
+	client := newTestClient(t, srv.URL)
+	params := &UpdateEmailParams{Subject: "Updated"}
+
+	if _, err := client.Emails.Update(context.Background(), "em_1", params, WithIdempotencyKey("update-1")); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := client.Emails.Update(context.Background(), "em_1", params); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got := rec.keys["PATCH /emails/em_1"]
+	if len(got) != 2 || got[0] != "update-1" || got[1] == "" {
+		t.Errorf("PATCH Idempotency-Keys = %q, want update-1 then a generated key", got)
+	}
+}
@@ -0,0 +1,106 @@
+package ekdsend
+
+import (
+	"context"
+	"iter"
+)
+
+// ListAll iterates over every email matching params, fetching further pages
+// as needed. A failed fetch is yielded as the final error; breaking out of
+// the loop stops fetching. The context is checked between pages.
+func (e *EmailsAPI) ListAll(ctx context.Context, params *ListEmailsParams) iter.Seq2[*Email, error] {
+	return listAll(ctx, func(ctx context.Context) (*PaginatedResponse[Email], error) {
+		return e.List(ctx, params)
+	})
+}
+
+// ListAllByCursor iterates over every email matching params by following
+// the server-issued NextCursor, starting at params.Cursor. Unlike offset
+// pages, emails created during the scan do not cause others to be skipped
+// or repeated. Iteration ends at the first page without a NextCursor.
+func (e *EmailsAPI) ListAllByCursor(ctx context.Context, params *ListEmailsParams) iter.Seq2[*Email, error] {
+	return listAll(ctx, func(ctx context.Context) (*PaginatedResponse[Email], error) {
+		page, err := e.List(ctx, params)
+		if err == nil && page.NextCursor == "" {
+			page.next = nil
+		}
+		return page, err
+	})
+}
+
+// ListAll iterates over every SMS message matching params; see
+// EmailsAPI.ListAll
+func (s *SMSAPI) ListAll(ctx context.Context, params *ListSMSParams) iter.Seq2[*SMS, error] {
+	return listAll(ctx, func(ctx context.Context) (*PaginatedResponse[SMS], error) {
+		return s.List(ctx, params)
+	})
+}
+
+// ListAll iterates over every call matching params; see EmailsAPI.ListAll
+func (v *VoiceAPI) ListAll(ctx context.Context, params *ListCallsParams) iter.Seq2[*VoiceCall, error] {
+	return listAll(ctx, func(ctx context.Context) (*PaginatedResponse[VoiceCall], error) {
+		return v.List(ctx, params)
+	})
+}
+
+// listAll yields the items of the first page and every page after it
+func listAll[T any](ctx context.Context, first func(context.Context) (*PaginatedResponse[T], error)) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		page, err := first(ctx)
+		for {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for i := range page.Data {
+				if !yield(&page.Data[i], nil) {
+					return
+				}
+			}
+
+			if err := ctx.Err(); err != nil {
+				yield(nil, contextError(err))
+				return
+			}
+
+			var more bool
+			page, more, err = page.Next(ctx)
+			if err == nil && !more {
+				return
+			}
+		}
+	}
+}
+
+// cancelByMetadata collects the IDs of every item matching a metadata
+// filter first, so cancellations don't shift the pages being read, then
+// cancels each one. Items that were sent or canceled in the meantime, which
+// the API answers with 404 or 409, are skipped. It returns the number
+// canceled, including on error.
+func cancelByMetadata[T any](ctx context.Context, key string, items iter.Seq2[*T, error],
+	id func(*T) string, cancel func(context.Context, string) error) (int, error) {
+	if key == "" {
+		return 0, newValidationError("metadata key is required", map[string]interface{}{"key": "required"})
+	}
+
+	var ids []string
+	for item, err := range items {
+		if err != nil {
+			return 0, err
+		}
+		ids = append(ids, id(item))
+	}
+
+	canceled := 0
+	for _, itemID := range ids {
+		if err := cancel(ctx, itemID); err != nil {
+			if IsNotFoundError(err) || IsConflictError(err) {
+				continue
+			}
+			return canceled, err
+		}
+		canceled++
+	}
+
+	return canceled, nil
+}
@@ -0,0 +1,75 @@
+package ekdsend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCancelByMetadata(t *testing.T) {
+	var mu sync.Mutex
+	var query string
+	var canceled []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet:
+			query = r.URL.RawQuery
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": []map[string]string{
+					{"id": "em_1", "status": "scheduled"},
+					{"id": "em_sent", "status": "scheduled"},
+					{"id": "em_gone", "status": "scheduled"},
+					{"id": "em_4", "status": "scheduled"},
+				},
+				"total": 4, "limit": 100, "offset": 0,
+			})
+		case r.Method == http.MethodDelete:
+			id := strings.TrimPrefix(r.URL.Path, "/emails/")
+			switch id {
+			case "em_sent":
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"message":"email is no longer scheduled","code":"CONFLICT"}`))
+			case "em_gone":
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message":"not found","code":"NOT_FOUND"}`))
+			default:
+				canceled = append(canceled, id)
+				writeEmail(w, id)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+
+	n, err := client.Emails.CancelByMetadata(context.Background(), "user_id", "123")
+	if err != nil {
+		t.Fatalf("CancelByMetadata: %v", err)
+	}
+	if n != 2 || strings.Join(canceled, ",") != "em_1,em_4" {
+		t.Errorf("canceled %d (%v), want 2 (em_1, em_4)", n, canceled)
+	}
+	if !strings.Contains(query, "status=scheduled") || !strings.Contains(query, "user_id") {
+		t.Errorf("list query %q does not filter by status and metadata", query)
+	}
+}
+
+func TestCancelByMetadataEmptyKey(t *testing.T) {
+	client := newTestClient(t, "http://api.ekdsend.test")
+
+	if _, err := client.Emails.CancelByMetadata(context.Background(), "", "123"); !IsValidationError(err) {
+		t.Errorf("Emails.CancelByMetadata error = %v, want a ValidationError", err)
+	}
+	if _, err := client.SMS.CancelByMetadata(context.Background(), "", "123"); !IsValidationError(err) {
+		t.Errorf("SMS.CancelByMetadata error = %v, want a ValidationError", err)
+	}
+}
@@ -0,0 +1,150 @@
+package ekdsend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+)
+
+// Logger receives the client's diagnostic output. Debugf is called for
+// every request and response; Errorf for failures the client recovers
+// from or cannot return to the caller, such as a failed audit log write,
+// and for warnings such as an unrecognized status while polling.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// WithLogger routes the client's diagnostic output to logger. Request logs
+// include the method, path, status, duration and request ID; the
+// Authorization header is always redacted. It takes precedence over the
+// stdout logger enabled by WithDebug.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// NewSlogLogger adapts a *slog.Logger to Logger, logging at debug and
+// error level
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return slogLogger{logger: logger}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l slogLogger) Debugf(format string, args ...interface{}) {
+	l.logger.Log(context.Background(), slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+
+func (l slogLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Log(context.Background(), slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+// writerLogger prints each message on its own line with the [EKDSend]
+// prefix. It backs WithDebug.
+type writerLogger struct {
+	w io.Writer
+}
+
+func (l writerLogger) Debugf(format string, args ...interface{}) {
+	fmt.Fprintf(l.w, "[EKDSend] "+format+"\n", args...)
+}
+
+func (l writerLogger) Errorf(format string, args ...interface{}) {
+	fmt.Fprintf(l.w, "[EKDSend] Error: "+format+"\n", args...)
+}
+
+// defaultLogger is used by WithDebug when no Logger was given
+func defaultLogger() Logger {
+	return writerLogger{w: os.Stdout}
+}
+
+func (c *Client) debugf(format string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Debugf(format, args...)
+	}
+}
+
+func (c *Client) errorf(format string, args ...interface{}) {
+	if c.logger != nil {
+		c.logger.Errorf(format, args...)
+	}
+}
+
+// WithRedactedFields masks the named JSON fields, such as "to" or
+// "metadata", in logged request and response bodies. Fields are matched by
+// name at any depth. Only log output is affected; requests are sent
+// unchanged.
+func WithRedactedFields(fields []string) ClientOption {
+	return func(c *Client) {
+		if c.redactedFields == nil {
+			c.redactedFields = make(map[string]struct{}, len(fields))
+		}
+		for _, field := range fields {
+			c.redactedFields[field] = struct{}{}
+		}
+	}
+}
+
+// logHeaders returns header for logging, with the Authorization value
+// masked down to the key's mode prefix and last four characters
+func (c *Client) logHeaders(header http.Header) http.Header {
+	if c.logger == nil {
+		return nil
+	}
+
+	logged := header.Clone()
+	if logged.Get("Authorization") != "" {
+		logged.Set("Authorization", "Bearer "+maskAPIKey(c.apiKey))
+	}
+	return logged
+}
+
+// logBody returns a JSON body for logging, with the fields configured by
+// WithRedactedFields masked. Bodies that are not JSON are logged as-is.
+// Without a logger it returns "" so bodies, which may hold large
+// attachments, are not copied for nothing.
+func (c *Client) logBody(body []byte) string {
+	if c.logger == nil {
+		return ""
+	}
+	if len(c.redactedFields) == 0 {
+		return string(body)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	masked, err := json.Marshal(c.redactValue(v))
+	if err != nil {
+		return string(body)
+	}
+	return string(masked)
+}
+
+// redactValue replaces the values of redacted fields within v
+func (c *Client) redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := c.redactedFields[key]; ok {
+				v[key] = redacted
+				continue
+			}
+			v[key] = c.redactValue(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = c.redactValue(value)
+		}
+	}
+	return v
+}
@@ -0,0 +1,59 @@
+package ekdsend
+
+import (
+	"strings"
+	"time"
+)
+
+// MetricsCollector receives one observation per API call, for export to
+// Prometheus or another monitoring system. endpoint is the method and the
+// path with IDs replaced by "{id}", e.g. "GET /emails/{id}", so it is safe
+// to use as a metric label. status is the final HTTP status, or 0 when no
+// response was received; dur covers every attempt and the backoff between
+// them; attempts is 1 plus the number of retries.
+//
+// ObserveRequest is called from the requesting goroutine and must be safe
+// for concurrent use.
+type MetricsCollector interface {
+	ObserveRequest(endpoint string, status int, dur time.Duration, attempts int)
+}
+
+// WithMetrics reports every API call to collector
+func WithMetrics(collector MetricsCollector) ClientOption {
+	return func(c *Client) {
+		c.metrics = collector
+	}
+}
+
+// staticSegments are path segments that follow a collection but name an
+// action or singleton rather than an ID
+var staticSegments = map[string]bool{
+	"batch":      true,
+	"bulk":       true,
+	"raw":        true,
+	"check":      true,
+	"download":   true,
+	"reputation": true,
+}
+
+// observe reports a completed call to the metrics collector
+func (c *Client) observe(method, path string, status int, dur time.Duration, attempts int) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.ObserveRequest(method+" "+endpointPath(path), status, dur, attempts)
+}
+
+// endpointPath strips the query from path and replaces the segment after
+// each collection name with "{id}"
+func endpointPath(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segments); i += 2 {
+		if !staticSegments[segments[i]] {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,80 @@
+package ekdsend
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+)
+
+// requiredRawHeaders are the headers RFC 5322 requires in every message
+var requiredRawHeaders = []string{"From", "Date"}
+
+// validateRawMIME checks that raw is a parseable message with the required
+// headers and, for multipart content, a well-formed part structure
+func validateRawMIME(raw []byte) error {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range requiredRawHeaders {
+		if msg.Header.Get(name) == "" {
+			return fmt.Errorf("missing %s header", name)
+		}
+	}
+	if _, err := mail.ParseAddressList(msg.Header.Get("From")); err != nil {
+		return fmt.Errorf("invalid From header: %w", err)
+	}
+	if _, err := msg.Header.Date(); err != nil {
+		return fmt.Errorf("invalid Date header: %w", err)
+	}
+
+	contentType := msg.Header.Get("Content-Type")
+	if contentType == "" {
+		return nil
+	}
+	return validateMIMEPart(contentType, msg.Body)
+}
+
+// validateMIMEPart walks multipart bodies recursively to make sure every
+// boundary is present and every part is readable
+func validateMIMEPart(contentType string, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid Content-Type: %w", err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return nil
+	}
+	if params["boundary"] == "" {
+		return fmt.Errorf("%s without boundary", mediaType)
+	}
+
+	reader := multipart.NewReader(body, params["boundary"])
+	parts := 0
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("malformed %s body: %w", mediaType, err)
+		}
+		parts++
+
+		if partType := part.Header.Get("Content-Type"); partType != "" {
+			if err := validateMIMEPart(partType, part); err != nil {
+				return err
+			}
+		}
+	}
+	if parts == 0 {
+		return fmt.Errorf("%s body has no parts", mediaType)
+	}
+
+	return nil
+}
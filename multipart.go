@@ -0,0 +1,129 @@
+package ekdsend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// MultipartAttachment is an attachment streamed from a reader by
+// SendMultipart instead of being base64-encoded into the JSON body
+type MultipartAttachment struct {
+	Filename    string
+	ContentType string
+	Content     io.Reader
+}
+
+// SendMultipart sends an email as multipart/form-data, streaming each
+// attachment's Content directly into the request body. Unlike Send, the
+// attachments are never held in memory or base64-encoded, which makes it
+// the better choice for large files.
+//
+// Because the body is streamed it cannot be replayed, so SendMultipart
+// does not retry failed requests. Attachments in params.Attachments are
+// still sent inline alongside the streamed ones.
+func (e *EmailsAPI) SendMultipart(ctx context.Context, params *SendEmailParams, attachments []MultipartAttachment, opts ...RequestOption) (*Email, error) {
+	if err := e.client.checkTestMode(); err != nil {
+		return nil, err
+	}
+	for i, attachment := range attachments {
+		if attachment.Filename == "" {
+			return nil, fmt.Errorf("attachment %d: filename is required", i)
+		}
+		if attachment.Content == nil {
+			return nil, fmt.Errorf("attachment %d: content is required", i)
+		}
+	}
+
+	if err := e.client.checkAttachmentCount(len(params.Attachments) + len(attachments)); err != nil {
+		return nil, err
+	}
+
+	params = e.prepare(params)
+	if err := e.check(params); err != nil {
+		return nil, err
+	}
+
+	if e.client.simulate {
+		email := e.client.simulateEmail(params)
+		e.client.sent("emails", params.recipients(), email.ID, string(email.Status), nil, nil)
+		return email, nil
+	}
+
+	if e.client.suppressionGuard {
+		if err := e.client.checkSuppressed(ctx, params.recipients()); err != nil {
+			return nil, err
+		}
+	}
+
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	form := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeMultipartEmail(form, payload, attachments))
+	}()
+
+	var resp Email
+	var meta ResponseMeta
+
+	err = e.client.requestStream(ctx, http.MethodPost, "/emails", form.FormDataContentType(), pr, envelope(&resp),
+		withMeta(&meta, withParamsKey(params.IdempotencyKey, opts))...)
+	e.client.sent("emails", params.recipients(), resp.ID, string(resp.Status), &meta, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// writeMultipartEmail writes the JSON payload followed by one file part per
+// attachment
+func writeMultipartEmail(form *multipart.Writer, payload []byte, attachments []MultipartAttachment) error {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="payload"`)
+	header.Set("Content-Type", "application/json")
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(payload); err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="attachments"; filename="%s"`, escapeQuotes(attachment.Filename)))
+		header.Set("Content-Type", contentType)
+		part, err := form.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, attachment.Content); err != nil {
+			return fmt.Errorf("failed to read attachment %s: %w", attachment.Filename, err)
+		}
+	}
+
+	return form.Close()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
@@ -0,0 +1,71 @@
+package ekdsend
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// syncFailWriter fails Sync the way *os.File does on a pipe
+type syncFailWriter struct {
+	bytes.Buffer
+}
+
+func (w *syncFailWriter) Sync() error {
+	return errors.New("sync /dev/stdout: invalid argument")
+}
+
+func newEmailListServer(t *testing.T, n int) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		emails := make([]map[string]string, n)
+		for i := range emails {
+			emails[i] = map[string]string{"id": fmt.Sprintf("em_%d", i+1), "status": "delivered"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": emails, "total": n, "limit": n, "offset": 0,
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestStreamNDJSONDoesNotSync(t *testing.T) {
+	srv := newEmailListServer(t, 150)
+	client := newTestClient(t, srv.URL)
+
+	var w syncFailWriter
+	n, err := client.Emails.StreamNDJSON(context.Background(), &ListEmailsParams{Limit: 150}, &w)
+	if err != nil {
+		t.Fatalf("StreamNDJSON: %v", err)
+	}
+	if n != 150 {
+		t.Errorf("wrote %d emails, want 150", n)
+	}
+	if lines := strings.Count(w.String(), "\n"); lines != 150 {
+		t.Errorf("wrote %d lines, want 150", lines)
+	}
+}
+
+func TestStreamNDJSONFlushesBufferedWriter(t *testing.T) {
+	srv := newEmailListServer(t, 3)
+	client := newTestClient(t, srv.URL)
+
+	var out bytes.Buffer
+	bw := bufio.NewWriterSize(&out, 64*1024)
+	if _, err := client.Emails.StreamNDJSON(context.Background(), &ListEmailsParams{Limit: 3}, bw); err != nil {
+		t.Fatalf("StreamNDJSON: %v", err)
+	}
+	if bw.Buffered() != 0 || strings.Count(out.String(), "\n") != 3 {
+		t.Errorf("buffered writer not flushed: %d bytes buffered, output %q", bw.Buffered(), out.String())
+	}
+}
@@ -0,0 +1,143 @@
+package ekdsend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Notification channels reported in NotifyResult
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+	ChannelVoice = "voice"
+)
+
+// DefaultNotifyPollInterval is how often Notify checks delivery status
+const DefaultNotifyPollInterval = 5 * time.Second
+
+// NotifyChannel is one step of a Notify escalation. Exactly one of Email,
+// SMS or Call must be set.
+type NotifyChannel struct {
+	Email *SendEmailParams
+	SMS   *SendSMSParams
+	Call  *CreateCallParams
+
+	// WaitForDelivery is how long to wait for the message to be delivered
+	// (or the call completed) before escalating to the next channel. Zero
+	// accepts the channel as soon as the API accepts the message.
+	WaitForDelivery time.Duration
+}
+
+// NotifyParams are the parameters for Notify
+type NotifyParams struct {
+	// Channels are tried in order until one succeeds
+	Channels []NotifyChannel
+
+	// PollInterval is how often delivery status is checked while waiting.
+	// Defaults to DefaultNotifyPollInterval.
+	PollInterval time.Duration
+}
+
+// NotifyResult reports the channel that succeeded and its message
+type NotifyResult struct {
+	Channel string
+	Email   *Email
+	SMS     *SMS
+	Call    *VoiceCall
+
+	// Failures holds the error of every channel tried before the one
+	// that succeeded
+	Failures []error
+}
+
+// Notify delivers a critical notification by escalating through channels
+// in order, e.g. email, then SMS, then a voice call. Each channel is sent
+// and, when WaitForDelivery is set, polled until it is delivered, fails,
+// or the wait elapses; on failure or timeout the next channel is tried.
+// It returns the first channel that succeeded, or an error describing
+// every failure when none did.
+func (c *Client) Notify(ctx context.Context, params *NotifyParams) (*NotifyResult, error) {
+	if len(params.Channels) == 0 {
+		return nil, errors.New("at least one notification channel is required")
+	}
+
+	interval := params.PollInterval
+	if interval <= 0 {
+		interval = DefaultNotifyPollInterval
+	}
+
+	var failures []error
+	for i, channel := range params.Channels {
+		result, err := c.notifyChannel(ctx, channel, interval)
+		if err == nil {
+			result.Failures = failures
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return nil, contextError(ctx.Err())
+		}
+		failures = append(failures, fmt.Errorf("channel %d: %w", i, err))
+	}
+
+	return nil, fmt.Errorf("all notification channels failed: %w", errors.Join(failures...))
+}
+
+func (c *Client) notifyChannel(ctx context.Context, channel NotifyChannel, interval time.Duration) (*NotifyResult, error) {
+	wait := channel.WaitForDelivery
+	poll := []PollOption{WithPollInterval(interval), WithMaxPollDuration(wait)}
+
+	switch {
+	case channel.Email != nil:
+		email, err := c.Emails.Send(ctx, channel.Email)
+		if err != nil {
+			return nil, err
+		}
+		if wait > 0 {
+			id := email.ID
+			if email, err = c.Emails.WaitUntilDelivered(ctx, id, poll...); err != nil {
+				return nil, fmt.Errorf("email %s: %w", id, err)
+			}
+			if email.Status != EmailStatusDelivered {
+				return nil, fmt.Errorf("email %s: ended with status %q", id, email.Status)
+			}
+		}
+		return &NotifyResult{Channel: ChannelEmail, Email: email}, nil
+
+	case channel.SMS != nil:
+		sms, err := c.SMS.Send(ctx, channel.SMS)
+		if err != nil {
+			return nil, err
+		}
+		if wait > 0 {
+			id := sms.ID
+			if sms, err = c.SMS.WaitUntilDelivered(ctx, id, poll...); err != nil {
+				return nil, fmt.Errorf("sms %s: %w", id, err)
+			}
+			if sms.Status != SMSStatusDelivered {
+				return nil, fmt.Errorf("sms %s: ended with status %q", id, sms.Status)
+			}
+		}
+		return &NotifyResult{Channel: ChannelSMS, SMS: sms}, nil
+
+	case channel.Call != nil:
+		call, err := c.Calls.Create(ctx, channel.Call)
+		if err != nil {
+			return nil, err
+		}
+		if wait > 0 {
+			id := call.ID
+			if call, err = c.Calls.WaitUntilCompleted(ctx, id, poll...); err != nil {
+				return nil, fmt.Errorf("call %s: %w", id, err)
+			}
+			if call.Status != CallStatusCompleted {
+				return nil, fmt.Errorf("call %s: ended with status %q", id, call.Status)
+			}
+		}
+		return &NotifyResult{Channel: ChannelVoice, Call: call}, nil
+
+	default:
+		return nil, errors.New("notification channel has no Email, SMS or Call configured")
+	}
+}
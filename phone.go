@@ -0,0 +1,46 @@
+package ekdsend
+
+import "fmt"
+
+// maxE164Digits is the longest number allowed by E.164, country code
+// included
+const maxE164Digits = 15
+
+// ValidatePhoneNumber checks that s is an E.164 number: a "+" followed by
+// a country code and subscriber number, 15 digits at most and not starting
+// with 0, e.g. "+14155551234". Spaces, dashes and parentheses are not
+// accepted. It returns a *ValidationError describing the problem.
+func ValidatePhoneNumber(s string) error {
+	return validatePhoneField("phone_number", s)
+}
+
+// validatePhoneField validates a phone number, reporting errors against
+// field
+func validatePhoneField(field, s string) error {
+	if msg := e164Problem(s); msg != "" {
+		return newValidationError(fmt.Sprintf("invalid %s %q: %s", field, s, msg), map[string]interface{}{field: msg})
+	}
+	return nil
+}
+
+// e164Problem describes why s is not an E.164 number, or returns ""
+func e164Problem(s string) string {
+	switch {
+	case s == "":
+		return "required"
+	case s[0] != '+':
+		return "must start with + and the country code"
+	case len(s) < 3:
+		return "too short"
+	case len(s)-1 > maxE164Digits:
+		return fmt.Sprintf("must have at most %d digits", maxE164Digits)
+	case s[1] == '0':
+		return "country code cannot start with 0"
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return "must contain only digits after the +"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,189 @@
+package ekdsend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultPollInterval is the default delay between status checks
+const DefaultPollInterval = 5 * time.Second
+
+// DefaultMaxPollDuration is how long the WaitUntil helpers poll before
+// returning ErrPollTimeout, unless WithMaxPollDuration is given
+const DefaultMaxPollDuration = 30 * time.Minute
+
+// ErrPollTimeout is returned by the WaitUntil helpers when the maximum poll
+// duration elapses before a terminal status is reached
+var ErrPollTimeout = errors.New("ekdsend: status did not become terminal in time")
+
+// PollOption configures the WaitUntil helpers
+type PollOption func(*pollConfig)
+
+type pollConfig struct {
+	interval    time.Duration
+	multiplier  float64
+	maxInterval time.Duration
+	maxDuration time.Duration
+}
+
+// WithPollInterval sets the delay between status checks. Defaults to
+// DefaultPollInterval.
+func WithPollInterval(d time.Duration) PollOption {
+	return func(pc *pollConfig) {
+		pc.interval = d
+	}
+}
+
+// WithPollBackoff multiplies the poll interval by multiplier after every
+// check, up to maxInterval (unbounded when zero)
+func WithPollBackoff(multiplier float64, maxInterval time.Duration) PollOption {
+	return func(pc *pollConfig) {
+		pc.multiplier = multiplier
+		pc.maxInterval = maxInterval
+	}
+}
+
+// WithMaxPollDuration stops polling with ErrPollTimeout once d has elapsed.
+// Defaults to DefaultMaxPollDuration. Zero or a negative d removes the cap,
+// so polling continues until the context is done.
+func WithMaxPollDuration(d time.Duration) PollOption {
+	return func(pc *pollConfig) {
+		pc.maxDuration = d
+	}
+}
+
+func newPollConfig(opts []PollOption) pollConfig {
+	pc := pollConfig{interval: DefaultPollInterval, maxDuration: DefaultMaxPollDuration}
+	for _, opt := range opts {
+		opt(&pc)
+	}
+	if pc.interval <= 0 {
+		pc.interval = DefaultPollInterval
+	}
+	return pc
+}
+
+// next returns the interval to wait after the current one
+func (pc *pollConfig) next(interval time.Duration) time.Duration {
+	if pc.multiplier <= 1 {
+		return interval
+	}
+	interval = time.Duration(float64(interval) * pc.multiplier)
+	if pc.maxInterval > 0 && interval > pc.maxInterval {
+		interval = pc.maxInterval
+	}
+	return interval
+}
+
+// WaitUntilDelivered polls an email until its status is terminal and
+// returns it. A terminal status is not necessarily a successful one:
+// check Status for EmailStatusDelivered. Each check goes through the
+// client's rate limiter.
+func (e *EmailsAPI) WaitUntilDelivered(ctx context.Context, emailID string, opts ...PollOption) (*Email, error) {
+	return pollUntilTerminal(ctx, e.client, "email", EmailStatusDelivered,
+		func(ctx context.Context) (*Email, error) { return e.Get(ctx, emailID) },
+		func(email *Email) EmailStatus { return email.Status },
+		opts)
+}
+
+// WaitUntilDelivered polls an SMS until its status is terminal and returns
+// it. Check Status for SMSStatusDelivered.
+func (s *SMSAPI) WaitUntilDelivered(ctx context.Context, smsID string, opts ...PollOption) (*SMS, error) {
+	return pollUntilTerminal(ctx, s.client, "sms", SMSStatusDelivered,
+		func(ctx context.Context) (*SMS, error) { return s.Get(ctx, smsID) },
+		func(sms *SMS) SMSStatus { return sms.Status },
+		opts)
+}
+
+// WaitUntilCompleted polls a call until it has ended and returns it. Check
+// Status for CallStatusCompleted.
+func (v *VoiceAPI) WaitUntilCompleted(ctx context.Context, callID string, opts ...PollOption) (*VoiceCall, error) {
+	return pollUntilTerminal(ctx, v.client, "call", CallStatusCompleted,
+		func(ctx context.Context) (*VoiceCall, error) { return v.Get(ctx, callID) },
+		func(call *VoiceCall) CallStatus { return call.Status },
+		opts)
+}
+
+// pollUntilTerminal fetches a resource until its status is terminal. When
+// the maximum duration elapses, the last fetched resource is returned with
+// ErrPollTimeout.
+func pollUntilTerminal[T any, S deliveryStatus](ctx context.Context, c *Client, kind string, success S,
+	get func(context.Context) (*T, error), status func(*T) S, opts []PollOption) (*T, error) {
+	pc := newPollConfig(opts)
+	classify := statusClassifier(c, kind, success)
+
+	var deadline time.Time
+	if pc.maxDuration > 0 {
+		deadline = c.clock.Now().Add(pc.maxDuration)
+	}
+
+	interval := pc.interval
+	for {
+		item, err := get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if classify(string(status(item))) != statusPending {
+			return item, nil
+		}
+
+		wait := interval
+		if !deadline.IsZero() {
+			remaining := deadline.Sub(c.clock.Now())
+			if remaining <= 0 {
+				return item, fmt.Errorf("%w: %s still %q after %s", ErrPollTimeout, kind, status(item), pc.maxDuration)
+			}
+			if wait > remaining {
+				wait = remaining
+			}
+		}
+		if err := c.sleep(ctx, wait); err != nil {
+			return item, contextError(err)
+		}
+		interval = pc.next(interval)
+	}
+}
+
+// statusClass is how a polling helper treats a status
+type statusClass int
+
+const (
+	statusPending statusClass = iota
+	statusSucceeded
+	statusFailed
+)
+
+// deliveryStatus is implemented by EmailStatus, SMSStatus and CallStatus
+type deliveryStatus interface {
+	~string
+	IsKnown() bool
+	IsTerminal() bool
+}
+
+// statusClassifier treats success as success and every other terminal
+// status as failure. Unknown statuses keep polling unless the client was
+// configured with WithUnknownStatusTerminal; each one is logged once per
+// classifier, so a poll loop warns about it once rather than on every tick.
+func statusClassifier[S deliveryStatus](c *Client, kind string, success S) func(string) statusClass {
+	warned := map[string]bool{}
+	return func(status string) statusClass {
+		s := S(status)
+		switch {
+		case s == success:
+			return statusSucceeded
+		case s.IsTerminal():
+			return statusFailed
+		case !s.IsKnown():
+			if !warned[status] {
+				warned[status] = true
+				c.errorf("Unknown %s status %q", kind, status)
+			}
+			if c.unknownStatusTerminal {
+				return statusFailed
+			}
+		}
+		return statusPending
+	}
+}
@@ -0,0 +1,90 @@
+package ekdsend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newTestProxy returns a forward proxy that answers every request itself,
+// recording the target URL and Proxy-Authorization it received
+func newTestProxy(t *testing.T) (*httptest.Server, *[]string, *[]string) {
+	t.Helper()
+
+	var targets, auths []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		targets = append(targets, r.URL.String())
+		auths = append(auths, r.Header.Get("Proxy-Authorization"))
+		writeEmail(w, "em_1")
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy, &targets, &auths
+}
+
+func TestWithProxy(t *testing.T) {
+	proxy, targets, auths := newTestProxy(t)
+
+	u, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.User = url.UserPassword("u", "p")
+
+	client := newTestClient(t, "http://api.ekdsend.test/v1", WithProxy(u.String()))
+	if _, err := client.Emails.Get(context.Background(), "em_1"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	if len(*targets) != 1 {
+		t.Fatalf("proxy saw %d requests, want 1", len(*targets))
+	}
+	if want := "http://api.ekdsend.test/v1/emails/em_1"; (*targets)[0] != want {
+		t.Errorf("proxied URL = %q, want %q", (*targets)[0], want)
+	}
+	if want := "Basic dTpw"; (*auths)[0] != want {
+		t.Errorf("Proxy-Authorization = %q, want %q", (*auths)[0], want)
+	}
+
+	cfg := client.Config()
+	if want := "http://u:xxxxx@" + u.Host; cfg.ProxyURL != want || cfg.ProxyFunc {
+		t.Errorf("Config() ProxyURL, ProxyFunc = %q, %t, want %q, false", cfg.ProxyURL, cfg.ProxyFunc, want)
+	}
+}
+
+func TestWithProxyFunc(t *testing.T) {
+	proxy, targets, _ := newTestProxy(t)
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTestClient(t, "http://api.ekdsend.test/v1", WithProxyFunc(func(r *http.Request) (*url.URL, error) {
+		if strings.HasSuffix(r.URL.Host, ".test") {
+			return proxyURL, nil
+		}
+		return nil, nil
+	}))
+	if _, err := client.Emails.Get(context.Background(), "em_1"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(*targets) != 1 {
+		t.Fatalf("proxy saw %d requests, want 1", len(*targets))
+	}
+
+	if cfg := client.Config(); cfg.ProxyURL != "" || !cfg.ProxyFunc {
+		t.Errorf("Config() ProxyURL, ProxyFunc = %q, %t, want \"\", true", cfg.ProxyURL, cfg.ProxyFunc)
+	}
+}
+
+func TestWithProxyInvalidURL(t *testing.T) {
+	_, err := New(testAPIKey, WithProxy("http://user:secret@"))
+	if err == nil {
+		t.Fatal("New succeeded with an invalid proxy URL")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("error %q leaks the proxy password", err)
+	}
+}
@@ -0,0 +1,57 @@
+package ekdsend
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ArrayQueryFormat controls how slice parameters such as Tags are encoded
+// in list queries
+type ArrayQueryFormat string
+
+const (
+	// ArrayFormatCSV joins values with commas: tags=a,b
+	ArrayFormatCSV ArrayQueryFormat = "csv"
+	// ArrayFormatRepeat repeats the parameter: tags=a&tags=b
+	ArrayFormatRepeat ArrayQueryFormat = "repeat"
+	// ArrayFormatBrackets repeats the parameter with a [] suffix: tags[]=a&tags[]=b
+	ArrayFormatBrackets ArrayQueryFormat = "brackets"
+)
+
+// WithArrayQueryFormat sets how slice parameters are encoded in list
+// queries. Defaults to ArrayFormatCSV; an unknown format makes New return
+// an error.
+func WithArrayQueryFormat(format ArrayQueryFormat) ClientOption {
+	return func(c *Client) {
+		switch format {
+		case ArrayFormatCSV, ArrayFormatRepeat, ArrayFormatBrackets:
+			c.arrayFormat = format
+		default:
+			c.setOptErr(fmt.Errorf("unknown array query format %q", format))
+		}
+	}
+}
+
+// setArrayQuery encodes values under key in the client's array format
+func (c *Client) setArrayQuery(query url.Values, key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+
+	switch c.arrayFormat {
+	case ArrayFormatRepeat:
+		query[key] = append([]string(nil), values...)
+	case ArrayFormatBrackets:
+		query[key+"[]"] = append([]string(nil), values...)
+	default:
+		query.Set(key, strings.Join(values, ","))
+	}
+}
+
+// setMetadataQuery encodes a metadata filter as metadata[key]=value params
+func setMetadataQuery(query url.Values, metadata map[string]string) {
+	for key, value := range metadata {
+		query.Set(fmt.Sprintf("metadata[%s]", key), value)
+	}
+}
@@ -0,0 +1,72 @@
+package ekdsend
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// minAdaptiveRateLimit is the lowest limit WithAdaptiveRateLimit backs
+	// off to, in requests per second
+	minAdaptiveRateLimit rate.Limit = 1
+
+	// adaptiveRateLimitCooldown is how long the adaptive limiter waits
+	// after lowering the limit before lowering it again, so a burst of
+	// concurrent 429s counts once
+	adaptiveRateLimitCooldown = time.Second
+)
+
+// WithAdaptiveRateLimit halves the client's rate limit, down to one request
+// per second, each time the API answers 429 Too Many Requests. Concurrent
+// 429s within a second lower the limit only once. The limit is never raised
+// again automatically; call SetRateLimit to restore it.
+func WithAdaptiveRateLimit() ClientOption {
+	return func(c *Client) {
+		c.adaptiveRateLimit = true
+	}
+}
+
+// SetRateLimit changes the client's rate limit to r requests per second
+// with bursts of b, e.g. when the account's limit changes. It is safe to
+// call while requests are in flight; requests already waiting keep their
+// reservation. A limiter passed with WithRateLimiter is reconfigured in
+// place.
+func (c *Client) SetRateLimit(r rate.Limit, b int) {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+
+	now := c.clock.Now()
+	c.rateLimiter.SetLimitAt(now, r)
+	c.rateLimiter.SetBurstAt(now, b)
+}
+
+// adaptRateLimit lowers the rate limit after a 429 when
+// WithAdaptiveRateLimit is set
+func (c *Client) adaptRateLimit(resp *response) {
+	if !c.adaptiveRateLimit || resp == nil || resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+
+	now := c.clock.Now()
+	if now.Sub(c.rateLoweredAt) < adaptiveRateLimitCooldown {
+		return
+	}
+
+	limit := c.rateLimiter.Limit()
+	lowered := limit / 2
+	if lowered < minAdaptiveRateLimit {
+		lowered = minAdaptiveRateLimit
+	}
+	if lowered >= limit {
+		return
+	}
+
+	c.rateLimiter.SetLimitAt(now, lowered)
+	c.rateLoweredAt = now
+	c.debugf("Rate limited by the API, lowering client rate limit from %g to %g requests/second", float64(limit), float64(lowered))
+}
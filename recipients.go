@@ -0,0 +1,78 @@
+package ekdsend
+
+import (
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+)
+
+// WithoutRecipientNormalization sends To, CC and BCC exactly as given
+// instead of normalizing them with NormalizeRecipients
+func WithoutRecipientNormalization() ClientOption {
+	return func(c *Client) {
+		c.skipNormalization = true
+	}
+}
+
+// NormalizeRecipients cleans up the To, CC and BCC lists of params in
+// place: whitespace is trimmed, domains are lowercased and each address is
+// kept only once, in the first of To, CC and BCC it appears in, so nobody
+// receives the email twice. Display names are preserved. It returns a
+// *ValidationError if an address is malformed or no recipient remains.
+// Every email send path, including batch, multipart and template sends,
+// applies it unless the client was created with
+// WithoutRecipientNormalization.
+func NormalizeRecipients(params *SendEmailParams) error {
+	errs := map[string]interface{}{}
+	seen := map[string]bool{}
+
+	params.To = normalizeAddresses(errs, seen, "to", params.To)
+	params.CC = normalizeAddresses(errs, seen, "cc", params.CC)
+	params.BCC = normalizeAddresses(errs, seen, "bcc", params.BCC)
+
+	if len(errs) == 0 && len(seen) == 0 {
+		errs["to"] = "at least one recipient is required"
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	fields := make([]string, 0, len(errs))
+	for field := range errs {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return newValidationError("invalid recipients: "+strings.Join(fields, ", "), errs)
+}
+
+// normalizeAddresses returns list normalized, without the addresses already
+// in seen, recording malformed addresses in errs
+func normalizeAddresses(errs map[string]interface{}, seen map[string]bool, field string, list []string) []string {
+	if list == nil {
+		return nil
+	}
+
+	out := make([]string, 0, len(list))
+	for i, raw := range list {
+		addr, err := mail.ParseAddress(strings.TrimSpace(raw))
+		if err != nil {
+			errs[fmt.Sprintf("%s[%d]", field, i)] = fmt.Sprintf("invalid address %q", raw)
+			continue
+		}
+
+		local, domain, _ := strings.Cut(addr.Address, "@")
+		addr.Address = local + "@" + strings.ToLower(domain)
+		if seen[addr.Address] {
+			continue
+		}
+		seen[addr.Address] = true
+
+		if addr.Name == "" {
+			out = append(out, addr.Address)
+		} else {
+			out = append(out, addr.String())
+		}
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package ekdsend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRecipientsNormalizedOnEverySendPath(t *testing.T) {
+	type recipients struct {
+		To  []string `json:"to"`
+		CC  []string `json:"cc"`
+		BCC []string `json:"bcc"`
+	}
+	var got []recipients
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/emails/batch" {
+			var body struct {
+				Emails []recipients `json:"emails"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			got = append(got, body.Emails...)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": []map[string]interface{}{
+				{"index": 0, "data": map[string]string{"id": "em_1"}},
+			}})
+			return
+		}
+		var body recipients
+		json.NewDecoder(r.Body).Decode(&body)
+		got = append(got, body)
+		writeEmail(w, "em_1")
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL)
+	ctx := context.Background()
+
+	params := testEmailParams()
+	params.To = []string{" a@Example.COM "}
+	params.CC = []string{"a@example.com", "b@example.com"}
+	params.BCC = []string{"b@EXAMPLE.com"}
+
+	if _, err := client.Emails.Send(ctx, params); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if _, err := client.Emails.BatchSend(ctx, []*SendEmailParams{params}); err != nil {
+		t.Fatalf("BatchSend: %v", err)
+	}
+	if _, err := client.Emails.SendTemplate(ctx, &SendTemplateParams{
+		TemplateID: "tmpl_1",
+		To:         params.To,
+		CC:         params.CC,
+		BCC:        params.BCC,
+	}); err != nil {
+		t.Fatalf("SendTemplate: %v", err)
+	}
+
+	want := recipients{To: []string{"a@example.com"}, CC: []string{"b@example.com"}}
+	if len(got) != 3 {
+		t.Fatalf("API received %d emails, want 3", len(got))
+	}
+	for i, r := range got {
+		if len(r.BCC) == 0 {
+			r.BCC = nil
+		}
+		if !reflect.DeepEqual(r, want) {
+			t.Errorf("email %d recipients = %+v, want %+v", i, r, want)
+		}
+	}
+	if params.To[0] != " a@Example.COM " {
+		t.Errorf("caller's params were modified: To = %q", params.To)
+	}
+}
@@ -0,0 +1,252 @@
+package ekdsend
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// redacted replaces the API key in recorded interactions
+const redacted = "[REDACTED]"
+
+// Interaction is a recorded request and the response it received. A
+// cassette is a stream of Interactions encoded as JSON, one per line.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the request half of an Interaction. URL is the path
+// and query relative to the API host, so a cassette replays against any
+// base URL.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the response half of an Interaction
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// WithRequestRecorder writes every HTTP round trip the client makes to w
+// as a cassette that NewReplayClient can serve later, e.g. to reproduce a
+// support issue in a test. The API key is replaced with "[REDACTED]" in
+// headers and bodies. Gzip-encoded bodies, as sent and received with
+// WithCompression, are recorded decompressed. Each attempt of a retried
+// request is recorded.
+func WithRequestRecorder(w io.Writer) ClientOption {
+	return func(c *Client) {
+		c.recorder = &recorder{w: w}
+	}
+}
+
+type recorder struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// recordingTransport records round trips through next
+type recordingTransport struct {
+	next     http.RoundTripper
+	recorder *recorder
+	apiKey   string
+	basePath string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		reqBody, err = io.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	reqHeader, reqBody := decodedBody(req.Header, reqBody)
+	respHeader, respBody := decodedBody(resp.Header, respBody)
+	t.recorder.write(Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     relativeURI(req, t.basePath),
+			Headers: t.sanitizeHeader(reqHeader),
+			Body:    t.sanitize(string(reqBody)),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    t.sanitizeHeader(respHeader),
+			Body:       t.sanitize(string(respBody)),
+		},
+	})
+
+	return resp, nil
+}
+
+// decodedBody returns a copy of header and the body with gzip content
+// encoding removed, so cassettes hold readable JSON that replays without
+// WithCompression. A body that fails to decode is returned as is.
+func decodedBody(header http.Header, body []byte) (http.Header, []byte) {
+	header = header.Clone()
+	decoded, err := decompressBody(header, body)
+	if err != nil {
+		return header, body
+	}
+	return header, decoded
+}
+
+func (t *recordingTransport) sanitize(s string) string {
+	return strings.ReplaceAll(s, t.apiKey, redacted)
+}
+
+func (t *recordingTransport) sanitizeHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, values := range out {
+		for i, value := range values {
+			values[i] = t.sanitize(value)
+		}
+	}
+	return out
+}
+
+// write appends one interaction to the cassette. Recording errors are
+// ignored so they never fail the API call being recorded.
+func (r *recorder) write(interaction Interaction) {
+	line, err := json.Marshal(interaction)
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, _ = r.w.Write(append(line, '\n'))
+}
+
+// NewReplayClient returns a client that serves the responses of a cassette
+// written by WithRequestRecorder instead of calling the API. Requests are
+// matched by method, path and query, and each recorded interaction is
+// served once, in recorded order. A request with no remaining recording
+// fails with a NotFoundError whose Code is "REPLAY_MISS".
+func NewReplayClient(r io.Reader, opts ...ClientOption) (*Client, error) {
+	replay := &replayTransport{interactions: map[string][]Interaction{}}
+
+	dec := json.NewDecoder(r)
+	for {
+		var interaction Interaction
+		if err := dec.Decode(&interaction); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		key := replayKey(interaction.Request.Method, interaction.Request.URL)
+		replay.interactions[key] = append(replay.interactions[key], interaction)
+	}
+
+	opts = append([]ClientOption{WithHTTPClient(&http.Client{Transport: replay})}, opts...)
+	c, err := New("ek_test_replay", opts...)
+	if err != nil {
+		return nil, err
+	}
+	replay.basePath = basePath(c.baseURL)
+	return c, nil
+}
+
+// replayTransport serves recorded responses
+type replayTransport struct {
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+	basePath     string
+}
+
+func replayKey(method, uri string) string {
+	return method + " " + uri
+}
+
+// relativeURI returns the path and query of req relative to the path of
+// the client's base URL, e.g. "/emails?limit=20" for ".../v1/emails?limit=20"
+func relativeURI(req *http.Request, basePath string) string {
+	uri := req.URL.RequestURI()
+	if rest, ok := strings.CutPrefix(uri, basePath); ok && strings.HasPrefix(rest, "/") {
+		return rest
+	}
+	return uri
+}
+
+// basePath returns the path component of a base URL without a trailing slash
+func basePath(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	key := replayKey(req.Method, relativeURI(req, t.basePath))
+
+	t.mu.Lock()
+	queue := t.interactions[key]
+	var recorded *RecordedResponse
+	if len(queue) > 0 {
+		recorded = &queue[0].Response
+		t.interactions[key] = queue[1:]
+	}
+	t.mu.Unlock()
+
+	if recorded == nil {
+		body, _ := json.Marshal(map[string]interface{}{
+			"error": map[string]string{
+				"message": fmt.Sprintf("no recorded response for %s", key),
+				"code":    "REPLAY_MISS",
+			},
+		})
+		recorded = &RecordedResponse{
+			StatusCode: http.StatusNotFound,
+			Headers:    http.Header{"Content-Type": {"application/json"}},
+			Body:       string(body),
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Headers.Clone(),
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
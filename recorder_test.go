@@ -0,0 +1,61 @@
+package ekdsend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecorderDecodesCompressedBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		io.WriteString(zw, `{"data":{"id":"em_1","status":"queued"}}`)
+		zw.Close()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	var cassette bytes.Buffer
+	client := newTestClient(t, srv.URL, WithCompression(), WithRequestRecorder(&cassette))
+	params := testEmailParams()
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var interaction Interaction
+	if err := json.Unmarshal(cassette.Bytes(), &interaction); err != nil {
+		t.Fatalf("decoding cassette: %v", err)
+	}
+	if !strings.Contains(interaction.Request.Body, params.Subject) || interaction.Request.Headers.Get("Content-Encoding") != "" {
+		t.Errorf("recorded request = %q with Content-Encoding %q, want the decompressed JSON",
+			interaction.Request.Body, interaction.Request.Headers.Get("Content-Encoding"))
+	}
+	if !json.Valid([]byte(interaction.Response.Body)) || interaction.Response.Headers.Get("Content-Encoding") != "" {
+		t.Errorf("recorded response = %q with Content-Encoding %q, want the decompressed JSON",
+			interaction.Response.Body, interaction.Response.Headers.Get("Content-Encoding"))
+	}
+
+	replay, err := NewReplayClient(&cassette)
+	if err != nil {
+		t.Fatalf("NewReplayClient: %v", err)
+	}
+	email, err := replay.Emails.Send(context.Background(), params)
+	if err != nil {
+		t.Fatalf("replayed Send: %v", err)
+	}
+	if email.ID != "em_1" {
+		t.Errorf("replayed ID = %q, want em_1", email.ID)
+	}
+}
@@ -0,0 +1,129 @@
+package ekdsend
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RequestOption configures a single API call
+type RequestOption func(*requestConfig)
+
+type requestConfig struct {
+	idempotencyKey string
+	timeout        time.Duration
+	metas          []*ResponseMeta
+	headers        http.Header
+}
+
+// ResponseMeta describes the HTTP response that answered a call
+type ResponseMeta struct {
+	StatusCode int
+	RequestID  string
+
+	// Region is the datacenter or PoP that handled the request, from the
+	// X-EKDSend-Region header, e.g. for correlating latency with WithRegion
+	Region string
+
+	// Attempts is the number of HTTP attempts made, including retries
+	Attempts int
+
+	// Duration is the time from the first attempt to the final response,
+	// including backoff between retries
+	Duration time.Duration
+
+	Header http.Header
+}
+
+// WithResponseMeta fills meta with details of the final HTTP response once
+// the call returns, including when the API answered with an error. meta is
+// left untouched when no response was received.
+func WithResponseMeta(meta *ResponseMeta) RequestOption {
+	return func(rc *requestConfig) {
+		rc.metas = append(rc.metas, meta)
+	}
+}
+
+// recordMeta copies the final response details into every requested meta
+func (rc *requestConfig) recordMeta(resp *response, attempts int, duration time.Duration) {
+	for _, meta := range rc.metas {
+		*meta = ResponseMeta{
+			StatusCode: resp.StatusCode,
+			RequestID:  resp.Header.Get("x-request-id"),
+			Region:     resp.Header.Get("X-EKDSend-Region"),
+			Attempts:   attempts,
+			Duration:   duration,
+			Header:     resp.Header,
+		}
+	}
+}
+
+// WithIdempotencyKey sets the Idempotency-Key header of a write request.
+// The API returns the original result for repeated requests with the same
+// key instead of performing the operation again, so a send retried after
+// a timeout is not delivered twice.
+//
+// When no key is given, POST, PUT and PATCH requests get a generated key
+// that is reused across the client's internal retries. See
+// WithBatchIdempotencyKey for batch sends.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(rc *requestConfig) {
+		rc.idempotencyKey = key
+	}
+}
+
+// WithRequestTimeout bounds this call, including retries, by d instead of
+// the client's timeouts. It replaces both the http.Client Timeout set with
+// WithTimeout and the overall timeout, so it can extend as well as shorten
+// them, e.g. to give a slow call creation more time than emails get.
+// A deadline on ctx still applies.
+func WithRequestTimeout(d time.Duration) RequestOption {
+	return func(rc *requestConfig) {
+		rc.timeout = d
+	}
+}
+
+func newRequestConfig(opts []RequestOption) requestConfig {
+	var rc requestConfig
+	for _, opt := range opts {
+		opt(&rc)
+	}
+	return rc
+}
+
+// withParamsKey puts the idempotency key from a params struct ahead of
+// opts, so an explicit WithIdempotencyKey option takes precedence
+func withParamsKey(key string, opts []RequestOption) []RequestOption {
+	if key == "" {
+		return opts
+	}
+	return append([]RequestOption{WithIdempotencyKey(key)}, opts...)
+}
+
+// withMeta appends a WithResponseMeta option for meta to a copy of opts,
+// leaving any meta requested by the caller in place
+func withMeta(meta *ResponseMeta, opts []RequestOption) []RequestOption {
+	return append(append([]RequestOption(nil), opts...), WithResponseMeta(meta))
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// httpClientFor returns the HTTP client for a request. A per-request
+// timeout is enforced on the context, so it gets a copy of the client
+// without its own Timeout.
+func (c *Client) httpClientFor(rc requestConfig) *http.Client {
+	if rc.timeout <= 0 || c.httpClient.Timeout == 0 {
+		return c.httpClient
+	}
+	hc := *c.httpClient
+	hc.Timeout = 0
+	return &hc
+}
@@ -0,0 +1,99 @@
+package ekdsend
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Zero BaseDelay and
+// Multiplier fields take the defaults (1s and 2); a zero MaxDelay leaves
+// the backoff uncapped.
+type RetryPolicy struct {
+	// MaxAttempts is the number of retries after the initial attempt.
+	// Zero disables retries.
+	MaxAttempts int `json:"max_attempts"`
+
+	// BaseDelay is the wait before the first retry
+	BaseDelay time.Duration `json:"base_delay"`
+
+	// MaxDelay caps the wait between retries
+	MaxDelay time.Duration `json:"max_delay,omitempty"`
+
+	// Multiplier grows the delay after each retry
+	Multiplier float64 `json:"multiplier"`
+
+	// Jitter randomizes each delay between half and all of its value, so
+	// clients that failed together do not retry in lockstep
+	Jitter bool `json:"jitter"`
+
+	// RetryableFunc decides whether a response or transport error is
+	// retried. resp is nil when err is set. Defaults to retrying transport
+	// errors, 429 and 5xx responses.
+	RetryableFunc func(resp *http.Response, err error) bool `json:"-"`
+}
+
+// DefaultRetryPolicy is the policy used unless WithRetryPolicy is given:
+// three retries after 1s, 2s and 4s
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: defaultMaxRetries,
+	BaseDelay:   time.Second,
+	Multiplier:  2,
+}
+
+// WithRetryPolicy sets how failed requests are retried, e.g.
+// RetryPolicy{MaxAttempts: 0} to disable retries for interactive requests.
+// A Retry-After from the API still lengthens the wait.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		if policy.MaxAttempts < 0 {
+			policy.MaxAttempts = 0
+		}
+		if policy.BaseDelay <= 0 {
+			policy.BaseDelay = DefaultRetryPolicy.BaseDelay
+		}
+		if policy.Multiplier <= 0 {
+			policy.Multiplier = DefaultRetryPolicy.Multiplier
+		}
+		c.retryPolicy = policy
+	}
+}
+
+// retryable reports whether the outcome of an attempt should be retried
+func (p *RetryPolicy) retryable(resp *response, err error) bool {
+	if p.RetryableFunc == nil {
+		return err != nil || resp.StatusCode == 429 || resp.StatusCode >= 500
+	}
+
+	var httpResp *http.Response
+	if resp != nil {
+		httpResp = &http.Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       io.NopCloser(bytes.NewReader(resp.Body)),
+		}
+	}
+	return p.RetryableFunc(httpResp, err)
+}
+
+// delay returns the backoff before retry number attempt (zero-based)
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := float64(p.BaseDelay)
+	for i := 0; i < attempt; i++ {
+		d *= p.Multiplier
+		if p.MaxDelay > 0 && d >= float64(p.MaxDelay) {
+			break
+		}
+	}
+
+	delay := time.Duration(d)
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter && delay > 0 {
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	}
+	return delay
+}
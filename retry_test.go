@@ -0,0 +1,104 @@
+package ekdsend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, Multiplier: 2, MaxDelay: 5 * time.Second}
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := policy.delay(attempt); got != want {
+			t.Errorf("delay(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+
+	policy.Jitter = true
+	for i := 0; i < 100; i++ {
+		if got := policy.delay(2); got < 2*time.Second || got > 4*time.Second {
+			t.Fatalf("jittered delay(2) = %s, want between 2s and 4s", got)
+		}
+	}
+}
+
+func TestWithRetryPolicyDefaults(t *testing.T) {
+	client := newTestClient(t, "http://api.ekdsend.test", WithRetryPolicy(RetryPolicy{MaxAttempts: -1}))
+
+	policy := client.retryPolicy
+	if policy.MaxAttempts != 0 || policy.BaseDelay != DefaultRetryPolicy.BaseDelay || policy.Multiplier != DefaultRetryPolicy.Multiplier {
+		t.Errorf("retryPolicy = %+v, want no retries with default delays", policy)
+	}
+}
+
+// statusSequence answers with each status in turn, then 200
+func statusSequence(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		writeEmail(w, "em_1")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestRetryPolicyDisabled(t *testing.T) {
+	srv, requests := statusSequence(t, http.StatusServiceUnavailable)
+
+	client := newTestClient(t, srv.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 0}))
+	if _, err := client.Emails.Get(context.Background(), "em_1"); !IsServerError(err) {
+		t.Errorf("Get error = %v, want ServerError", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server received %d requests, want 1", n)
+	}
+}
+
+func TestRetryPolicyMaxAttempts(t *testing.T) {
+	srv, requests := statusSequence(t, 500, 502, 503, 504)
+
+	client := newTestClient(t, srv.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+	if _, err := client.Emails.Get(context.Background(), "em_1"); !IsServerError(err) {
+		t.Errorf("Get error = %v, want ServerError", err)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("server received %d requests, want 3", n)
+	}
+}
+
+func TestRetryableFunc(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		RetryableFunc: func(resp *http.Response, err error) bool {
+			return resp != nil && resp.StatusCode == http.StatusConflict
+		},
+	}
+
+	srv, requests := statusSequence(t, http.StatusConflict)
+	client := newTestClient(t, srv.URL, WithRetryPolicy(policy))
+	if _, err := client.Emails.Get(context.Background(), "em_1"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server received %d requests, want 2", n)
+	}
+
+	srv, requests = statusSequence(t, http.StatusServiceUnavailable)
+	client = newTestClient(t, srv.URL, WithRetryPolicy(policy))
+	if _, err := client.Emails.Get(context.Background(), "em_1"); !IsServerError(err) {
+		t.Errorf("Get error = %v, want ServerError", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server received %d requests, want 1: RetryableFunc does not retry 503", n)
+	}
+}
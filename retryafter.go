@@ -0,0 +1,52 @@
+package ekdsend
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retryAfter returns how long the API asked the client to wait before
+// retrying, from the Retry-After header (delay-seconds or HTTP-date) or,
+// failing that, the retry_after field of the error body
+func retryAfter(resp *response, now time.Time) (time.Duration, bool) {
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+		return d, true
+	}
+
+	var errResp struct {
+		Error struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(resp.Body, &errResp) == nil && errResp.Error.RetryAfter > 0 {
+		return time.Duration(errResp.Error.RetryAfter) * time.Second, true
+	}
+	return 0, false
+}
+
+// parseRetryAfter parses a Retry-After header value. Dates in the past
+// yield a zero delay.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,131 @@
+package ekdsend
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sleepRecorder is a Clock that returns immediately from After and
+// records every wait it was asked for
+type sleepRecorder struct {
+	mu    sync.Mutex
+	waits []time.Duration
+}
+
+func (c *sleepRecorder) Now() time.Time { return time.Now() }
+
+func (c *sleepRecorder) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	c.waits = append(c.waits, d)
+	c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	ch <- time.Now()
+	return ch
+}
+
+func (c *sleepRecorder) recorded() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.waits...)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"5", 5 * time.Second, true},
+		{" 2 ", 2 * time.Second, true},
+		{"0", 0, true},
+		{"-1", 0, false},
+		{"", 0, false},
+		{"soon", 0, false},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %t, want %s, %t", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRetryAfterFromBody(t *testing.T) {
+	resp := &response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{},
+		Body:       []byte(`{"error":{"code":"RATE_LIMIT_EXCEEDED","retry_after":7}}`),
+	}
+	if got, ok := retryAfter(resp, time.Now()); got != 7*time.Second || !ok {
+		t.Errorf("retryAfter = %s, %t, want 7s, true", got, ok)
+	}
+
+	// The header wins over the body
+	resp.Header.Set("Retry-After", "2")
+	if got, ok := retryAfter(resp, time.Now()); got != 2*time.Second || !ok {
+		t.Errorf("retryAfter = %s, %t, want 2s, true", got, ok)
+	}
+}
+
+func TestRetryAfterDelaysRetry(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		n := requests
+		mu.Unlock()
+
+		if n == 1 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		writeEmail(w, "em_1")
+	}))
+	defer srv.Close()
+
+	clock := &sleepRecorder{}
+	client := newTestClient(t, srv.URL, WithClock(clock))
+	if _, err := client.Emails.Get(context.Background(), "em_1"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	waited := false
+	for _, d := range clock.recorded() {
+		if d == 3*time.Second {
+			waited = true
+		}
+	}
+	if !waited {
+		t.Errorf("waits = %v, want a 3s wait from Retry-After", clock.recorded())
+	}
+}
+
+func TestRateLimitErrorRetryAfter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "4")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(t, srv.URL, WithRetryPolicy(RetryPolicy{}))
+	_, err := client.Emails.Get(context.Background(), "em_1")
+
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("Get error = %v, want *RateLimitError", err)
+	}
+	if rateErr.RetryAfter != 4 {
+		t.Errorf("RetryAfter = %d, want 4", rateErr.RetryAfter)
+	}
+}
@@ -0,0 +1,43 @@
+package ekdsend
+
+import (
+	"context"
+	"time"
+)
+
+// Schedule sends params for delivery at at. The time is sent as UTC
+// RFC 3339, whatever its location; params.ScheduledAt is ignored. A time
+// that is not in the future returns a ValidationError without sending.
+func (e *EmailsAPI) Schedule(ctx context.Context, params *SendEmailParams, at time.Time, opts ...RequestOption) (*Email, error) {
+	scheduledAt, err := e.client.scheduledAt(at)
+	if err != nil {
+		return nil, err
+	}
+
+	p := *params
+	p.ScheduledAt = scheduledAt
+	return e.Send(ctx, &p, opts...)
+}
+
+// Schedule sends params for delivery at at. The time is sent as UTC
+// RFC 3339, whatever its location; params.ScheduledAt is ignored. A time
+// that is not in the future returns a ValidationError without sending.
+func (s *SMSAPI) Schedule(ctx context.Context, params *SendSMSParams, at time.Time, opts ...RequestOption) (*SMS, error) {
+	scheduledAt, err := s.client.scheduledAt(at)
+	if err != nil {
+		return nil, err
+	}
+
+	p := *params
+	p.ScheduledAt = scheduledAt
+	return s.Send(ctx, &p, opts...)
+}
+
+// scheduledAt formats at for the scheduled_at field, rejecting times that
+// are not after the client clock's current time
+func (c *Client) scheduledAt(at time.Time) (string, error) {
+	if !at.After(c.clock.Now()) {
+		return "", newValidationError("scheduled time must be in the future", map[string]interface{}{"scheduled_at": at.UTC().Format(time.RFC3339)})
+	}
+	return at.UTC().Format(time.RFC3339), nil
+}
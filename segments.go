@@ -0,0 +1,107 @@
+package ekdsend
+
+// SMS character encodings
+const (
+	EncodingGSM7 = "GSM-7"
+	EncodingUCS2 = "UCS-2"
+)
+
+const (
+	// gsm7Basic is the GSM 03.38 default alphabet (without the escape code)
+	gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+	// gsm7Extended characters are sent as an escape code plus one septet
+	gsm7Extended = "\f^{}\\[~]|€"
+)
+
+// Characters per SMS segment. Messages that do not fit in one segment are
+// split into parts that each lose room to the concatenation header.
+const (
+	gsm7SegmentSize   = 160
+	gsm7MultipartSize = 153
+	ucs2SegmentSize   = 70
+	ucs2MultipartSize = 67
+)
+
+var (
+	gsm7BasicSet    = runeSet(gsm7Basic)
+	gsm7ExtendedSet = runeSet(gsm7Extended)
+)
+
+func runeSet(chars string) map[rune]bool {
+	set := make(map[rune]bool)
+	for _, r := range chars {
+		set[r] = true
+	}
+	return set
+}
+
+// smsEncoding returns the encoding a message requires: GSM-7 when every
+// character is in the GSM 03.38 alphabet or its extension table, UCS-2
+// otherwise
+func smsEncoding(message string) string {
+	for _, r := range message {
+		if !gsm7BasicSet[r] && !gsm7ExtendedSet[r] {
+			return EncodingUCS2
+		}
+	}
+	return EncodingGSM7
+}
+
+// fillEncoding derives Encoding from the message when the API omitted it
+func (s *SMS) fillEncoding() {
+	if s.Encoding == "" && s.Message != "" {
+		s.Encoding = smsEncoding(s.Message)
+	}
+}
+
+// EstimateSegments returns the number of SMS segments message will be sent
+// as, so the cost of a long message can be shown before it is sent.
+// GSM-7 messages fit 160 characters in one segment and 153 per part when
+// split; characters from the GSM-7 extension table, such as € and {, take
+// two. A single character outside GSM-7, such as an emoji or a non-Latin
+// letter, switches the whole message to UCS-2, which fits 70 UTF-16 code
+// units in one segment and 67 per part; emoji take two units. Characters
+// are never split across parts. An empty message has no segments.
+func EstimateSegments(message string) int {
+	if message == "" {
+		return 0
+	}
+
+	single, multi := gsm7SegmentSize, gsm7MultipartSize
+	width := func(r rune) int {
+		if gsm7ExtendedSet[r] {
+			return 2
+		}
+		return 1
+	}
+	if smsEncoding(message) == EncodingUCS2 {
+		single, multi = ucs2SegmentSize, ucs2MultipartSize
+		width = func(r rune) int {
+			if r > 0xFFFF {
+				return 2
+			}
+			return 1
+		}
+	}
+
+	total := 0
+	for _, r := range message {
+		total += width(r)
+	}
+	if total <= single {
+		return 1
+	}
+
+	segments, used := 1, 0
+	for _, r := range message {
+		w := width(r)
+		if used+w > multi {
+			segments++
+			used = 0
+		}
+		used += w
+	}
+	return segments
+}
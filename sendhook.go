@@ -0,0 +1,61 @@
+package ekdsend
+
+import "time"
+
+// SendRecord describes the outcome of one email, SMS or call sent by the
+// client, for logging alongside the API's request ID
+type SendRecord struct {
+	// Resource is "emails", "sms" or "calls"
+	Resource   string
+	Recipients []string
+	MessageID  string
+	Status     string
+
+	// RequestID, Duration and Attempts describe the HTTP request that
+	// carried the send; items of a batch share the batch request's values.
+	// They are empty for simulated sends.
+	RequestID string
+	Duration  time.Duration
+	Attempts  int
+
+	Err error
+}
+
+// WithSendHook calls hook after every send, successful or not, including
+// each item of a batch. Use it to log message IDs, statuses and request
+// IDs in one place. hook runs on the sending goroutine and must be safe
+// for concurrent use.
+func WithSendHook(hook func(SendRecord)) ClientOption {
+	return func(c *Client) {
+		c.sendHook = hook
+	}
+}
+
+// sent records the outcome of a send in the audit log, the send hook and
+// the debug log. meta is nil for simulated sends.
+func (c *Client) sent(resource string, recipients []string, messageID, status string, meta *ResponseMeta, err error) {
+	c.audit(resource, recipients, messageID, status, err)
+
+	record := SendRecord{
+		Resource:   resource,
+		Recipients: recipients,
+		MessageID:  messageID,
+		Status:     status,
+		Err:        err,
+	}
+	if meta != nil {
+		record.RequestID = meta.RequestID
+		record.Duration = meta.Duration
+		record.Attempts = meta.Attempts
+	}
+
+	if err != nil {
+		c.debugf("Send to %s failed in %s (request_id=%s): %v", resource, record.Duration, record.RequestID, err)
+	} else {
+		c.debugf("Sent %s message_id=%s status=%s in %s (request_id=%s)", resource, messageID, status, record.Duration, record.RequestID)
+	}
+
+	if c.sendHook != nil {
+		c.sendHook(record)
+	}
+}
@@ -0,0 +1,88 @@
+package ekdsend
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// WithSimulatedResponses makes send operations return synthetic, successful
+// objects with generated IDs instead of calling the API, so application
+// code paths can be load-tested locally. Only test keys (ek_test_) may use
+// it; New returns an error for live keys. Read operations such as Get and
+// List still call the API.
+func WithSimulatedResponses() ClientOption {
+	return func(c *Client) {
+		if !strings.HasPrefix(c.apiKey, "ek_test_") {
+			c.setOptErr(fmt.Errorf("simulated responses require a test API key (ek_test_)"))
+			return
+		}
+		c.simulate = true
+	}
+}
+
+// simulatedID returns a random ID with the given resource prefix
+func simulatedID(prefix string) string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+func (c *Client) simulateEmail(p *SendEmailParams) *Email {
+	email := &Email{
+		ID:         simulatedID("em_"),
+		Status:     EmailStatusQueued,
+		From:       p.From,
+		To:         p.To,
+		Subject:    p.Subject,
+		HTML:       p.HTML,
+		Text:       p.Text,
+		CC:         p.CC,
+		BCC:        p.BCC,
+		ReplyTo:    p.replyTo(),
+		Tags:       p.Tags,
+		Metadata:   p.Metadata,
+		RequireTLS: p.RequireTLS,
+		CreatedAt:  c.clock.Now().UTC(),
+	}
+	if p.ScheduledAt != "" {
+		email.Status = EmailStatusScheduled
+	}
+	return email
+}
+
+func (c *Client) simulateSMS(p *SendSMSParams) *SMS {
+	sms := &SMS{
+		ID:        simulatedID("sms_"),
+		Status:    SMSStatusQueued,
+		To:        p.To,
+		From:      p.From,
+		Message:   p.Message,
+		Segments:  EstimateSegments(p.Message),
+		Encoding:  smsEncoding(p.Message),
+		Metadata:  p.Metadata,
+		CreatedAt: c.clock.Now().UTC(),
+	}
+	if p.ScheduledAt != "" {
+		sms.Status = SMSStatusScheduled
+	}
+	return sms
+}
+
+func (c *Client) simulateCall(p *CreateCallParams) *VoiceCall {
+	return &VoiceCall{
+		ID:               simulatedID("call_"),
+		Status:           CallStatusQueued,
+		To:               p.To,
+		From:             p.From,
+		TTSMessage:       p.TTSMessage,
+		AudioURL:         p.AudioURL,
+		Voice:            string(p.Voice),
+		Language:         string(p.Language),
+		Record:           p.Record,
+		MachineDetection: p.MachineDetection,
+		Metadata:         p.Metadata,
+		CreatedAt:        c.clock.Now().UTC(),
+	}
+}
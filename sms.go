@@ -20,6 +20,25 @@ type SendSMSParams struct {
 	ScheduledAt string            `json:"scheduled_at,omitempty"`
 	WebhookURL  string            `json:"webhook_url,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
+
+	// IdempotencyKey is sent as the Idempotency-Key header; see
+	// WithIdempotencyKey
+	IdempotencyKey string `json:"-"`
+}
+
+// SendBulkSMSParams are the parameters for sending one message to many
+// recipients
+type SendBulkSMSParams struct {
+	To          []string          `json:"to"`
+	Message     string            `json:"message"`
+	From        string            `json:"from,omitempty"`
+	ScheduledAt string            `json:"scheduled_at,omitempty"`
+	WebhookURL  string            `json:"webhook_url,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+
+	// IdempotencyKey is sent as the Idempotency-Key header; see
+	// WithIdempotencyKey
+	IdempotencyKey string `json:"-"`
 }
 
 // ListSMSParams are the parameters for listing SMS messages
@@ -29,34 +48,133 @@ type ListSMSParams struct {
 	Status   string
 	FromDate string
 	ToDate   string
+	Metadata map[string]string
+
+	// Before and After are cursor tokens from PaginatedResponse.LastCursor
+	// and FirstCursor. When either is set, records are paged by creation
+	// time and ID instead of Offset.
+	Before string
+	After  string
 }
 
 // Send sends an SMS message
-func (s *SMSAPI) Send(ctx context.Context, params *SendSMSParams) (*SMS, error) {
-	var resp struct {
-		Data SMS `json:"data"`
+func (s *SMSAPI) Send(ctx context.Context, params *SendSMSParams, opts ...RequestOption) (*SMS, error) {
+	if err := s.client.checkTestMode(); err != nil {
+		return nil, err
 	}
+	if !s.client.skipValidation {
+		if err := validatePhoneField("to", params.To); err != nil {
+			return nil, err
+		}
+	}
+
+	p := *params
+	p.Metadata = s.client.withDefaultMetadata(p.Metadata)
+	params = &p
+
+	if s.client.simulate {
+		sms := s.client.simulateSMS(params)
+		s.client.sent("sms", []string{params.To}, sms.ID, string(sms.Status), nil, nil)
+		return sms, nil
+	}
+
+	var resp SMS
+	var meta ResponseMeta
 
-	err := s.client.Post(ctx, "/sms", params, &resp)
+	err := s.client.Post(ctx, "/sms", params, envelope(&resp), withMeta(&meta, withParamsKey(params.IdempotencyKey, opts))...)
+	s.client.sent("sms", []string{params.To}, resp.ID, string(resp.Status), &meta, err)
 	if err != nil {
 		return nil, err
 	}
 
-	return &resp.Data, nil
+	resp.fillEncoding()
+	return &resp, nil
 }
 
-// Get retrieves an SMS by ID
-func (s *SMSAPI) Get(ctx context.Context, smsID string) (*SMS, error) {
-	var resp struct {
-		Data SMS `json:"data"`
+// SendBulk sends the same message to every number in params.To in a single
+// request, fanned out by the API, so it costs one rate-limit token rather
+// than one per recipient. Rejected recipients are returned in Failed,
+// indexed by their position in To; an error is returned only when the
+// request as a whole fails.
+func (s *SMSAPI) SendBulk(ctx context.Context, params *SendBulkSMSParams, opts ...RequestOption) (*BatchResult[SMS], error) {
+	if err := s.client.checkTestMode(); err != nil {
+		return nil, err
+	}
+	if len(params.To) == 0 {
+		return nil, newValidationError("at least one recipient is required", map[string]interface{}{"to": "required"})
+	}
+	if !s.client.skipValidation {
+		for i, number := range params.To {
+			if err := validatePhoneField(fmt.Sprintf("to[%d]", i), number); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	err := s.client.Get(ctx, fmt.Sprintf("/sms/%s", smsID), nil, &resp)
+	p := *params
+	p.Metadata = s.client.withDefaultMetadata(p.Metadata)
+	params = &p
+
+	result := &BatchResult[SMS]{}
+
+	if s.client.simulate {
+		results := make([]batchItem[SMS], len(params.To))
+		for i, to := range params.To {
+			sms := s.client.simulateSMS(&SendSMSParams{
+				To:          to,
+				Message:     params.Message,
+				From:        params.From,
+				ScheduledAt: params.ScheduledAt,
+				Metadata:    params.Metadata,
+			})
+			s.client.sent("sms", []string{to}, sms.ID, string(sms.Status), nil, nil)
+			results[i] = batchItem[SMS]{Index: i, Data: sms}
+		}
+		result.add(results, 0, len(params.To))
+		return result, nil
+	}
+
+	var resp []batchItem[SMS]
+	var meta ResponseMeta
+
+	err := s.client.Post(ctx, "/sms/bulk", params, envelope(&resp), withMeta(&meta, withParamsKey(params.IdempotencyKey, opts))...)
 	if err != nil {
+		for _, to := range params.To {
+			s.client.sent("sms", []string{to}, "", "", &meta, err)
+		}
 		return nil, err
 	}
 
-	return &resp.Data, nil
+	for _, res := range resp {
+		if res.Index < 0 || res.Index >= len(params.To) {
+			continue
+		}
+		to := []string{params.To[res.Index]}
+		switch {
+		case res.Error != nil:
+			res.Error.requestID = meta.RequestID
+			s.client.sent("sms", to, "", "", &meta, res.Error.err())
+		case res.Data != nil:
+			res.Data.fillEncoding()
+			s.client.sent("sms", to, res.Data.ID, string(res.Data.Status), &meta, nil)
+		}
+	}
+
+	result.add(resp, 0, len(params.To))
+	return result, nil
+}
+
+// Get retrieves an SMS by ID
+func (s *SMSAPI) Get(ctx context.Context, smsID string, opts ...RequestOption) (*SMS, error) {
+	var resp SMS
+
+	err := s.client.Get(ctx, fmt.Sprintf("/sms/%s", smsID), nil, envelope(&resp), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.fillEncoding()
+	return &resp, nil
 }
 
 // List retrieves a paginated list of SMS messages
@@ -67,7 +185,9 @@ func (s *SMSAPI) List(ctx context.Context, params *ListSMSParams) (*PaginatedRes
 
 	query := url.Values{}
 	query.Set("limit", strconv.Itoa(params.Limit))
-	query.Set("offset", strconv.Itoa(params.Offset))
+	if err := setPageQuery(query, params.Offset, params.Before, params.After); err != nil {
+		return nil, err
+	}
 
 	if params.Status != "" {
 		query.Set("status", params.Status)
@@ -78,26 +198,51 @@ func (s *SMSAPI) List(ctx context.Context, params *ListSMSParams) (*PaginatedRes
 	if params.ToDate != "" {
 		query.Set("to_date", params.ToDate)
 	}
+	setMetadataQuery(query, params.Metadata)
 
 	var resp PaginatedResponse[SMS]
 	err := s.client.Get(ctx, "/sms", query, &resp)
 	if err != nil {
 		return nil, err
 	}
+	for i := range resp.Data {
+		resp.Data[i].fillEncoding()
+	}
+
+	resp.bindNext(params.Before, params.After, func(ctx context.Context, offset int, before string) (*PaginatedResponse[SMS], error) {
+		next := *params
+		next.Offset, next.Before = offset, before
+		return s.List(ctx, &next)
+	})
 
 	return &resp, nil
 }
 
 // Cancel cancels a scheduled SMS
 func (s *SMSAPI) Cancel(ctx context.Context, smsID string) (*SMS, error) {
-	var resp struct {
-		Data SMS `json:"data"`
-	}
+	var resp SMS
 
-	err := s.client.Delete(ctx, fmt.Sprintf("/sms/%s", smsID), &resp)
+	err := s.client.Delete(ctx, fmt.Sprintf("/sms/%s", smsID), envelope(&resp))
 	if err != nil {
 		return nil, err
 	}
 
-	return &resp.Data, nil
+	resp.fillEncoding()
+	return &resp, nil
+}
+
+// CancelByMetadata cancels every scheduled SMS message whose metadata has
+// key set to value; see EmailsAPI.CancelByMetadata
+func (s *SMSAPI) CancelByMetadata(ctx context.Context, key, value string) (int, error) {
+	return cancelByMetadata(ctx, key,
+		s.ListAll(ctx, &ListSMSParams{
+			Limit:    100,
+			Status:   string(SMSStatusScheduled),
+			Metadata: map[string]string{key: value},
+		}),
+		func(item *SMS) string { return item.ID },
+		func(ctx context.Context, id string) error {
+			_, err := s.Cancel(ctx, id)
+			return err
+		})
 }
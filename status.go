@@ -0,0 +1,168 @@
+package ekdsend
+
+// EmailStatus is the delivery status of an email
+type EmailStatus string
+
+const (
+	EmailStatusQueued     EmailStatus = "queued"
+	EmailStatusScheduled  EmailStatus = "scheduled"
+	EmailStatusSent       EmailStatus = "sent"
+	EmailStatusDelivered  EmailStatus = "delivered"
+	EmailStatusBounced    EmailStatus = "bounced"
+	EmailStatusFailed     EmailStatus = "failed"
+	EmailStatusComplained EmailStatus = "complained"
+	EmailStatusRejected   EmailStatus = "rejected"
+	EmailStatusCanceled   EmailStatus = "canceled"
+
+	// EmailStatusUnknown is returned by ParseEmailStatus for values this
+	// version of the SDK does not recognize
+	EmailStatusUnknown EmailStatus = "unknown"
+)
+
+var emailStatuses = map[EmailStatus]bool{
+	EmailStatusQueued:     false,
+	EmailStatusScheduled:  false,
+	EmailStatusSent:       false,
+	EmailStatusDelivered:  true,
+	EmailStatusBounced:    true,
+	EmailStatusFailed:     true,
+	EmailStatusComplained: true,
+	EmailStatusRejected:   true,
+	EmailStatusCanceled:   true,
+}
+
+// ParseEmailStatus converts a raw status string, mapping values this SDK
+// does not know to EmailStatusUnknown
+func ParseEmailStatus(s string) EmailStatus {
+	status := EmailStatus(s)
+	if !status.IsKnown() {
+		return EmailStatusUnknown
+	}
+	return status
+}
+
+// IsKnown reports whether the status is one this SDK recognizes
+func (s EmailStatus) IsKnown() bool {
+	_, ok := emailStatuses[s]
+	return ok
+}
+
+// IsTerminal reports whether the email will not change status again.
+// Unknown statuses are never terminal; polling helpers decide how to treat
+// them (see WithUnknownStatusTerminal).
+func (s EmailStatus) IsTerminal() bool {
+	return emailStatuses[s]
+}
+
+// SMSStatus is the delivery status of an SMS message
+type SMSStatus string
+
+const (
+	SMSStatusQueued      SMSStatus = "queued"
+	SMSStatusScheduled   SMSStatus = "scheduled"
+	SMSStatusSending     SMSStatus = "sending"
+	SMSStatusSent        SMSStatus = "sent"
+	SMSStatusDelivered   SMSStatus = "delivered"
+	SMSStatusUndelivered SMSStatus = "undelivered"
+	SMSStatusFailed      SMSStatus = "failed"
+	SMSStatusRejected    SMSStatus = "rejected"
+	SMSStatusCanceled    SMSStatus = "canceled"
+
+	// SMSStatusUnknown is returned by ParseSMSStatus for values this
+	// version of the SDK does not recognize
+	SMSStatusUnknown SMSStatus = "unknown"
+)
+
+var smsStatuses = map[SMSStatus]bool{
+	SMSStatusQueued:      false,
+	SMSStatusScheduled:   false,
+	SMSStatusSending:     false,
+	SMSStatusSent:        false,
+	SMSStatusDelivered:   true,
+	SMSStatusUndelivered: true,
+	SMSStatusFailed:      true,
+	SMSStatusRejected:    true,
+	SMSStatusCanceled:    true,
+}
+
+// ParseSMSStatus converts a raw status string, mapping values this SDK
+// does not know to SMSStatusUnknown
+func ParseSMSStatus(s string) SMSStatus {
+	status := SMSStatus(s)
+	if !status.IsKnown() {
+		return SMSStatusUnknown
+	}
+	return status
+}
+
+// IsKnown reports whether the status is one this SDK recognizes
+func (s SMSStatus) IsKnown() bool {
+	_, ok := smsStatuses[s]
+	return ok
+}
+
+// IsTerminal reports whether the message will not change status again
+func (s SMSStatus) IsTerminal() bool {
+	return smsStatuses[s]
+}
+
+// CallStatus is the status of a voice call
+type CallStatus string
+
+const (
+	CallStatusQueued     CallStatus = "queued"
+	CallStatusInitiated  CallStatus = "initiated"
+	CallStatusRinging    CallStatus = "ringing"
+	CallStatusInProgress CallStatus = "in-progress"
+	CallStatusCompleted  CallStatus = "completed"
+	CallStatusBusy       CallStatus = "busy"
+	CallStatusNoAnswer   CallStatus = "no-answer"
+	CallStatusFailed     CallStatus = "failed"
+	CallStatusCanceled   CallStatus = "canceled"
+
+	// CallStatusUnknown is returned by ParseCallStatus for values this
+	// version of the SDK does not recognize
+	CallStatusUnknown CallStatus = "unknown"
+)
+
+var callStatuses = map[CallStatus]bool{
+	CallStatusQueued:     false,
+	CallStatusInitiated:  false,
+	CallStatusRinging:    false,
+	CallStatusInProgress: false,
+	CallStatusCompleted:  true,
+	CallStatusBusy:       true,
+	CallStatusNoAnswer:   true,
+	CallStatusFailed:     true,
+	CallStatusCanceled:   true,
+}
+
+// ParseCallStatus converts a raw status string, mapping values this SDK
+// does not know to CallStatusUnknown
+func ParseCallStatus(s string) CallStatus {
+	status := CallStatus(s)
+	if !status.IsKnown() {
+		return CallStatusUnknown
+	}
+	return status
+}
+
+// IsKnown reports whether the status is one this SDK recognizes
+func (s CallStatus) IsKnown() bool {
+	_, ok := callStatuses[s]
+	return ok
+}
+
+// IsTerminal reports whether the call has ended
+func (s CallStatus) IsTerminal() bool {
+	return callStatuses[s]
+}
+
+// WithUnknownStatusTerminal makes polling helpers stop when they encounter
+// a status this SDK does not recognize, instead of polling until their
+// maximum wait elapses
+func WithUnknownStatusTerminal() ClientOption {
+	return func(c *Client) {
+		c.unknownStatusTerminal = true
+	}
+}
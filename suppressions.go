@@ -0,0 +1,209 @@
+package ekdsend
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// codeRecipientsSuppressed marks batch items skipped because every
+// recipient is on the suppression list
+const codeRecipientsSuppressed = "RECIPIENTS_SUPPRESSED"
+
+// maxSuppressionCheckSize is the number of addresses checked per request
+const maxSuppressionCheckSize = 1000
+
+// SuppressionReason is why an address is suppressed
+type SuppressionReason string
+
+// Suppression reasons
+const (
+	SuppressionUnsubscribe SuppressionReason = "unsubscribe"
+	SuppressionHardBounce  SuppressionReason = "hard_bounce"
+	SuppressionComplaint   SuppressionReason = "complaint"
+	SuppressionManual      SuppressionReason = "manual"
+)
+
+// Suppression is an address that is never emailed
+type Suppression struct {
+	Address   string            `json:"address"`
+	Reason    SuppressionReason `json:"reason"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// SuppressionsAPI provides access to the suppression list
+type SuppressionsAPI struct {
+	client *Client
+}
+
+// ListSuppressionsParams are the parameters for listing suppressions
+type ListSuppressionsParams struct {
+	Limit  int
+	Offset int
+	Reason SuppressionReason
+}
+
+// List retrieves a paginated list of suppressed addresses
+func (s *SuppressionsAPI) List(ctx context.Context, params *ListSuppressionsParams) (*PaginatedResponse[Suppression], error) {
+	if params == nil {
+		params = &ListSuppressionsParams{Limit: 20, Offset: 0}
+	}
+
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(params.Limit))
+	query.Set("offset", strconv.Itoa(params.Offset))
+	if params.Reason != "" {
+		query.Set("reason", string(params.Reason))
+	}
+
+	var resp PaginatedResponse[Suppression]
+	err := s.client.Get(ctx, "/suppressions", query, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.bindNext("", "", func(ctx context.Context, offset int, _ string) (*PaginatedResponse[Suppression], error) {
+		next := *params
+		next.Offset = offset
+		return s.List(ctx, &next)
+	})
+
+	return &resp, nil
+}
+
+// Add suppresses address so it is never emailed
+func (s *SuppressionsAPI) Add(ctx context.Context, address string, reason SuppressionReason) (*Suppression, error) {
+	normalized := normalizeAddress(address)
+	if normalized == "" {
+		return nil, newValidationError("address is required", map[string]interface{}{"address": "required"})
+	}
+
+	body := struct {
+		Address string            `json:"address"`
+		Reason  SuppressionReason `json:"reason,omitempty"`
+	}{Address: normalized, Reason: reason}
+
+	var resp Suppression
+	err := s.client.Post(ctx, "/suppressions", body, envelope(&resp))
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Remove takes address off the suppression list
+func (s *SuppressionsAPI) Remove(ctx context.Context, address string) error {
+	normalized := normalizeAddress(address)
+	if normalized == "" {
+		return newValidationError("address is required", map[string]interface{}{"address": "required"})
+	}
+	return s.client.Delete(ctx, fmt.Sprintf("/suppressions/%s", url.PathEscape(normalized)), nil)
+}
+
+// Check reports whether address is suppressed
+func (s *SuppressionsAPI) Check(ctx context.Context, address string) (bool, error) {
+	suppressed, err := s.client.suppressedAmong(ctx, []string{address})
+	if err != nil {
+		return false, err
+	}
+	return suppressed[normalizeAddress(address)], nil
+}
+
+// WithSuppressionGuard checks email recipients against the suppression
+// list before sending. Send, SendTemplate, SendMultipart and SendRaw return
+// a *SuppressedError without sending when any recipient is suppressed; in
+// BatchSend, SendThrottled and a BatchingSender such emails fail
+// individually with a *SuppressedError. It costs one extra request per
+// send or batch. WithAutoSuppressionFilter takes precedence for batches.
+func WithSuppressionGuard() ClientOption {
+	return func(c *Client) {
+		c.suppressionGuard = true
+	}
+}
+
+// checkSuppressed returns a *SuppressedError listing the suppressed
+// addresses among recipients, if any
+func (c *Client) checkSuppressed(ctx context.Context, recipients []string) error {
+	suppressed, err := c.suppressedAmong(ctx, recipients)
+	if err != nil {
+		return err
+	}
+
+	var blocked []string
+	for _, address := range recipients {
+		if suppressed[normalizeAddress(address)] {
+			blocked = append(blocked, address)
+		}
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+	return &SuppressedError{Recipients: blocked}
+}
+
+// WithAutoSuppressionFilter checks the recipients of batch sends against
+// the suppression list before sending and removes suppressed addresses.
+// Emails left without a To recipient are not sent and fail with the
+// RECIPIENTS_SUPPRESSED code; the removed addresses are reported with each
+// result. Lookups are deduplicated and batched within a send.
+func WithAutoSuppressionFilter() ClientOption {
+	return func(c *Client) {
+		c.suppressionFilter = true
+	}
+}
+
+// suppressedAmong returns the set of normalized addresses that are
+// suppressed, checking each distinct address once
+func (c *Client) suppressedAmong(ctx context.Context, addresses []string) (map[string]bool, error) {
+	seen := make(map[string]bool, len(addresses))
+	unique := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		normalized := normalizeAddress(address)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		unique = append(unique, normalized)
+	}
+
+	suppressed := make(map[string]bool)
+	for i := 0; i < len(unique); i += maxSuppressionCheckSize {
+		end := i + maxSuppressionCheckSize
+		if end > len(unique) {
+			end = len(unique)
+		}
+
+		body := struct {
+			Addresses []string `json:"addresses"`
+		}{Addresses: unique[i:end]}
+
+		var resp struct {
+			Suppressed []string `json:"suppressed"`
+		}
+
+		if err := c.Post(ctx, "/suppressions/check", body, envelope(&resp)); err != nil {
+			return nil, err
+		}
+		for _, address := range resp.Suppressed {
+			suppressed[normalizeAddress(address)] = true
+		}
+	}
+
+	return suppressed, nil
+}
+
+// normalizeAddress reduces an address to a comparable lowercase form,
+// dropping any display name
+func normalizeAddress(address string) string {
+	address = strings.TrimSpace(address)
+	if start := strings.LastIndex(address, "<"); start >= 0 {
+		if end := strings.LastIndex(address, ">"); end > start {
+			address = address[start+1 : end]
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(address))
+}
@@ -0,0 +1,100 @@
+package ekdsend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newSuppressionServer answers suppression checks with suppressed and
+// counts the emails that reach the send endpoints
+func newSuppressionServer(t *testing.T, suppressed ...string) (*httptest.Server, *int) {
+	t.Helper()
+
+	var sent int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/suppressions/check":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string][]string{"suppressed": suppressed},
+			})
+		case "/emails/batch":
+			var body struct {
+				Emails []json.RawMessage `json:"emails"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			items := make([]map[string]interface{}, len(body.Emails))
+			for i := range body.Emails {
+				sent++
+				items[i] = map[string]interface{}{"index": i, "data": map[string]string{"id": "em_1"}}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": items})
+		default:
+			sent++
+			writeEmail(w, "em_1")
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &sent
+}
+
+func TestSuppressionGuardSendPaths(t *testing.T) {
+	srv, sent := newSuppressionServer(t, "blocked@example.com")
+	client := newTestClient(t, srv.URL, WithSuppressionGuard())
+	ctx := context.Background()
+
+	params := testEmailParams()
+	params.CC = []string{"blocked@example.com"}
+
+	raw := []byte("From: sender@example.com\r\nDate: Mon, 02 Jan 2006 15:04:05 -0700\r\nSubject: Hi\r\n\r\nHello\r\n")
+
+	sends := map[string]func() error{
+		"Send": func() error {
+			_, err := client.Emails.Send(ctx, params)
+			return err
+		},
+		"SendMultipart": func() error {
+			_, err := client.Emails.SendMultipart(ctx, params, nil)
+			return err
+		},
+		"SendRaw": func() error {
+			_, err := client.Emails.SendRaw(ctx, "sender@example.com", []string{"Blocked@example.com"}, raw)
+			return err
+		},
+	}
+	for name, send := range sends {
+		err := send()
+		if !errors.Is(err, ErrSuppressed) {
+			t.Errorf("%s error = %v, want ErrSuppressed", name, err)
+		}
+	}
+	if *sent != 0 {
+		t.Errorf("%d emails reached the API, want 0", *sent)
+	}
+}
+
+func TestSuppressionGuardBatchSend(t *testing.T) {
+	srv, sent := newSuppressionServer(t, "blocked@example.com")
+	client := newTestClient(t, srv.URL, WithSuppressionGuard())
+
+	blocked := testEmailParams()
+	blocked.BCC = []string{"blocked@example.com"}
+
+	result, err := client.Emails.BatchSend(context.Background(), []*SendEmailParams{testEmailParams(), blocked})
+	if err != nil {
+		t.Fatalf("BatchSend: %v", err)
+	}
+	if len(result.Succeeded) != 1 || len(result.Failed) != 1 {
+		t.Fatalf("got %d succeeded and %d failed, want 1 and 1", len(result.Succeeded), len(result.Failed))
+	}
+	if failed := result.Failed[0]; failed.Index != 1 || !errors.Is(failed.Err, ErrSuppressed) {
+		t.Errorf("failed item = %d %v, want 1 ErrSuppressed", failed.Index, failed.Err)
+	}
+	if *sent != 1 {
+		t.Errorf("%d emails reached the API, want 1", *sent)
+	}
+}
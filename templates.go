@@ -0,0 +1,297 @@
+package ekdsend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TemplatesAPI provides access to server-side email templates
+type TemplatesAPI struct {
+	client *Client
+}
+
+// SendTemplateParams are the parameters for sending an email rendered from
+// a server-side template. The body, and the subject unless Subject is set,
+// come from the template; Variables fill in its placeholders. ReplyToList
+// takes precedence over ReplyTo, as in SendEmailParams.
+type SendTemplateParams struct {
+	TemplateID  string                 `json:"template_id"`
+	From        string                 `json:"from,omitempty"`
+	To          []string               `json:"to"`
+	Variables   map[string]interface{} `json:"variables,omitempty"`
+	Subject     string                 `json:"subject,omitempty"`
+	CC          []string               `json:"cc,omitempty"`
+	BCC         []string               `json:"bcc,omitempty"`
+	ReplyTo     string                 `json:"reply_to,omitempty"`
+	ReplyToList []string               `json:"-"`
+	Attachments []Attachment           `json:"attachments,omitempty"`
+	Headers     map[string]string      `json:"headers,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Metadata    map[string]string      `json:"metadata,omitempty"`
+	ScheduledAt string                 `json:"scheduled_at,omitempty"`
+
+	// IdempotencyKey is sent as the Idempotency-Key header; see
+	// WithIdempotencyKey
+	IdempotencyKey string `json:"-"`
+}
+
+// MarshalJSON encodes the params, folding ReplyToList into reply_to
+func (p SendTemplateParams) MarshalJSON() ([]byte, error) {
+	type params SendTemplateParams
+	out := params(p)
+	out.ReplyTo = p.replyTo()
+	return json.Marshal(out)
+}
+
+// replyTo resolves ReplyToList and ReplyTo as SendEmailParams does
+func (p *SendTemplateParams) replyTo() string {
+	return (&SendEmailParams{ReplyTo: p.ReplyTo, ReplyToList: p.ReplyToList}).replyTo()
+}
+
+// Validate checks params locally: TemplateID and at least one recipient
+// are required, and From, when set, every recipient and the reply-to
+// addresses must be valid. Errors are reported as for
+// SendEmailParams.Validate.
+func (p *SendTemplateParams) Validate() error {
+	errs := map[string]interface{}{}
+
+	if strings.TrimSpace(p.TemplateID) == "" {
+		errs["template_id"] = "required"
+	}
+	if p.From != "" {
+		if _, err := mail.ParseAddress(p.From); err != nil {
+			errs["from"] = fmt.Sprintf("invalid address %q", p.From)
+		}
+	}
+	if len(p.To) == 0 {
+		errs["to"] = "at least one recipient is required"
+	}
+	validateAddresses(errs, "to", p.To)
+	validateAddresses(errs, "cc", p.CC)
+	validateAddresses(errs, "bcc", p.BCC)
+	validateReplyTo(errs, p.ReplyTo, p.ReplyToList)
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	fields := make([]string, 0, len(errs))
+	for field := range errs {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return newValidationError("invalid template params: "+strings.Join(fields, ", "), errs)
+}
+
+// SendTemplate sends an email rendered from a server-side template. Emails
+// without a From use the client's from-address pool when one is set, and
+// the template's sender otherwise.
+func (e *EmailsAPI) SendTemplate(ctx context.Context, params *SendTemplateParams, opts ...RequestOption) (*Email, error) {
+	if err := e.client.checkTestMode(); err != nil {
+		return nil, err
+	}
+	if err := e.client.checkAttachmentCount(len(params.Attachments)); err != nil {
+		return nil, err
+	}
+
+	p := *params
+	params = &p
+	if params.From == "" && e.client.fromPool != nil {
+		params.From = e.client.fromPool.pick()
+	}
+	params.Metadata = e.client.withDefaultMetadata(params.Metadata)
+	params.Tags = e.client.withDefaultTags(params.Tags)
+
+	if !e.client.skipNormalization {
+		recipients := &SendEmailParams{To: params.To, CC: params.CC, BCC: params.BCC}
+		if err := NormalizeRecipients(recipients); err != nil {
+			return nil, err
+		}
+		params.To, params.CC, params.BCC = recipients.To, recipients.CC, recipients.BCC
+	}
+	if !e.client.skipValidation {
+		if err := params.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	recipients := (&SendEmailParams{To: params.To, CC: params.CC, BCC: params.BCC}).recipients()
+
+	if e.client.simulate {
+		email := e.client.simulateEmail(&SendEmailParams{
+			From:        params.From,
+			To:          params.To,
+			Subject:     params.Subject,
+			CC:          params.CC,
+			BCC:         params.BCC,
+			ReplyTo:     params.ReplyTo,
+			ReplyToList: params.ReplyToList,
+			Tags:        params.Tags,
+			Metadata:    params.Metadata,
+			ScheduledAt: params.ScheduledAt,
+		})
+		e.client.sent("emails", recipients, email.ID, string(email.Status), nil, nil)
+		return email, nil
+	}
+
+	if e.client.suppressionGuard {
+		if err := e.client.checkSuppressed(ctx, recipients); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp Email
+	var meta ResponseMeta
+
+	err := e.client.Post(ctx, "/emails", params, envelope(&resp), withMeta(&meta, withParamsKey(params.IdempotencyKey, opts))...)
+	e.client.sent("emails", recipients, resp.ID, string(resp.Status), &meta, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// TemplateParams are the parameters for creating or replacing a template.
+// Variables declares the placeholders the template expects; see
+// TemplatesAPI.Validate.
+type TemplateParams struct {
+	Name      string   `json:"name"`
+	Subject   string   `json:"subject"`
+	HTML      string   `json:"html,omitempty"`
+	Text      string   `json:"text,omitempty"`
+	Variables []string `json:"variables,omitempty"`
+}
+
+// ListTemplatesParams are the parameters for listing templates
+type ListTemplatesParams struct {
+	Limit  int
+	Offset int
+}
+
+// List retrieves a paginated list of templates
+func (t *TemplatesAPI) List(ctx context.Context, params *ListTemplatesParams) (*PaginatedResponse[Template], error) {
+	if params == nil {
+		params = &ListTemplatesParams{Limit: 20, Offset: 0}
+	}
+
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(params.Limit))
+	query.Set("offset", strconv.Itoa(params.Offset))
+
+	var resp PaginatedResponse[Template]
+	err := t.client.Get(ctx, "/templates", query, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.bindNext("", "", func(ctx context.Context, offset int, _ string) (*PaginatedResponse[Template], error) {
+		next := *params
+		next.Offset = offset
+		return t.List(ctx, &next)
+	})
+
+	return &resp, nil
+}
+
+// Create creates a template
+func (t *TemplatesAPI) Create(ctx context.Context, params *TemplateParams) (*Template, error) {
+	if strings.TrimSpace(params.Name) == "" {
+		return nil, newValidationError("template name is required", map[string]interface{}{"name": "required"})
+	}
+
+	var resp Template
+
+	err := t.client.Post(ctx, "/templates", params, envelope(&resp))
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Update replaces the name, subject, body and variables of a template
+func (t *TemplatesAPI) Update(ctx context.Context, templateID string, params *TemplateParams, opts ...RequestOption) (*Template, error) {
+	if strings.TrimSpace(params.Name) == "" {
+		return nil, newValidationError("template name is required", map[string]interface{}{"name": "required"})
+	}
+
+	var resp Template
+
+	err := t.client.Put(ctx, fmt.Sprintf("/templates/%s", templateID), params, envelope(&resp), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Delete deletes a template
+func (t *TemplatesAPI) Delete(ctx context.Context, templateID string) error {
+	return t.client.Delete(ctx, fmt.Sprintf("/templates/%s", templateID), nil)
+}
+
+// Get retrieves a template by ID
+func (t *TemplatesAPI) Get(ctx context.Context, templateID string) (*Template, error) {
+	var resp Template
+
+	err := t.client.Get(ctx, fmt.Sprintf("/templates/%s", templateID), nil, envelope(&resp))
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Validate checks data against the variables declared by a template and
+// returns a *ValidationError listing the missing and extra variables, so a
+// mismatch is caught before an email is rendered with blanks. Its Errors
+// map holds the sorted names under "missing" and "extra".
+func (t *TemplatesAPI) Validate(ctx context.Context, templateID string, data map[string]interface{}) error {
+	template, err := t.Get(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	return template.Validate(data)
+}
+
+// Validate checks data against the template's declared variables without
+// a request; see TemplatesAPI.Validate
+func (t *Template) Validate(data map[string]interface{}) error {
+	declared := make(map[string]bool, len(t.Variables))
+	var missing []string
+	for _, name := range t.Variables {
+		declared[name] = true
+		if _, ok := data[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	var extra []string
+	for name := range data {
+		if !declared[name] {
+			extra = append(extra, name)
+		}
+	}
+
+	if len(missing) == 0 && len(extra) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+
+	errs := map[string]interface{}{}
+	if len(missing) > 0 {
+		errs["missing"] = missing
+	}
+	if len(extra) > 0 {
+		errs["extra"] = extra
+	}
+	return newValidationError(fmt.Sprintf("template data does not match template %s: %d missing, %d extra variables", t.ID, len(missing), len(extra)), errs)
+}
@@ -0,0 +1,63 @@
+package ekdsend
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	reDropBlocks = regexp.MustCompile(`(?is)<(script|style|head)[^>]*>.*?</(script|style|head)>`)
+	reLink       = regexp.MustCompile(`(?is)<a\s[^>]*href\s*=\s*["']([^"']*)["'][^>]*>(.*?)</a>`)
+	reLineBreak  = regexp.MustCompile(`(?i)<br\s*/?>`)
+	reBlockEnd   = regexp.MustCompile(`(?i)</(p|div|h[1-6]|tr|table|ul|ol|blockquote)>`)
+	reListItem   = regexp.MustCompile(`(?i)<li[^>]*>`)
+	reTag        = regexp.MustCompile(`(?s)<[^>]*>`)
+	reSpaces     = regexp.MustCompile(`[ \t\r\f\v]+`)
+	reBlankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// GenerateText fills Text with a plain-text version of HTML when Text is
+// empty, as WithAutoPlainText does at send time. The conversion is
+// deterministic: links become "label (url)", block elements and <br>
+// become line breaks, and scripts and styles are dropped.
+func (p *SendEmailParams) GenerateText() {
+	if p.Text == "" && p.HTML != "" {
+		p.Text = htmlToText(p.HTML)
+	}
+}
+
+// htmlToText converts an HTML body into a readable plain-text alternative.
+// Links are rendered as "text (url)" and block elements become line breaks.
+func htmlToText(body string) string {
+	text := reDropBlocks.ReplaceAllString(body, "")
+
+	text = reLink.ReplaceAllStringFunc(text, func(m string) string {
+		parts := reLink.FindStringSubmatch(m)
+		href := strings.TrimSpace(parts[1])
+		label := strings.TrimSpace(reTag.ReplaceAllString(parts[2], ""))
+		switch {
+		case href == "" || strings.HasPrefix(href, "#"):
+			return label
+		case label == "" || label == href:
+			return href
+		default:
+			return label + " (" + href + ")"
+		}
+	})
+
+	text = reLineBreak.ReplaceAllString(text, "\n")
+	text = reListItem.ReplaceAllString(text, "\n- ")
+	text = reBlockEnd.ReplaceAllString(text, "\n\n")
+	text = reTag.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(reSpaces.ReplaceAllString(line, " "))
+	}
+	text = strings.Join(lines, "\n")
+	text = reBlankLines.ReplaceAllString(text, "\n\n")
+
+	return strings.TrimSpace(text)
+}
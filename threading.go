@@ -0,0 +1,44 @@
+package ekdsend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ThreadWith makes the email a reply to the message with the given
+// Message-ID, such as Email.MessageID of an earlier send, so mail clients
+// thread them together. It sets the In-Reply-To header and appends the ID
+// to References. messageID may be given with or without angle brackets; an
+// ID that is not of the form <left@right> returns a *ValidationError.
+func (p *SendEmailParams) ThreadWith(messageID string) error {
+	id, err := normalizeMessageID(messageID)
+	if err != nil {
+		return err
+	}
+
+	headers := make(map[string]string, len(p.Headers)+2)
+	for key, value := range p.Headers {
+		headers[key] = value
+	}
+	headers["In-Reply-To"] = id
+	switch refs := headers["References"]; {
+	case refs == "":
+		headers["References"] = id
+	case !strings.Contains(refs, id):
+		headers["References"] = refs + " " + id
+	}
+	p.Headers = headers
+	return nil
+}
+
+// normalizeMessageID returns messageID in angle brackets after checking it
+// has the RFC 5322 form left@right
+func normalizeMessageID(messageID string) (string, error) {
+	id := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(messageID), "<"), ">")
+
+	left, right, ok := strings.Cut(id, "@")
+	if !ok || left == "" || right == "" || strings.ContainsAny(id, " \t\r\n<>") || strings.Contains(right, "@") {
+		return "", newValidationError(fmt.Sprintf("invalid message ID %q", messageID), map[string]interface{}{"message_id": "must have the form <left@right>"})
+	}
+	return "<" + id + ">", nil
+}
@@ -0,0 +1,90 @@
+package ekdsend
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// TimeoutConfig groups every timeout the client applies. Zero fields keep
+// their defaults: the standard library's dial (30s), TLS handshake (10s)
+// and idle connection (90s) timeouts, no response header timeout, and the
+// overall and per-attempt timeouts set by other options, if any.
+type TimeoutConfig struct {
+	// Connect bounds establishing the TCP connection
+	Connect time.Duration
+
+	// TLSHandshake bounds the TLS handshake
+	TLSHandshake time.Duration
+
+	// ResponseHeader bounds the wait for response headers once the request
+	// has been written
+	ResponseHeader time.Duration
+
+	// IdleConn is how long an idle keep-alive connection stays in the pool
+	// before it is closed
+	IdleConn time.Duration
+
+	// Overall bounds a whole API call, including rate limiting, every retry
+	// and the backoff between them
+	Overall time.Duration
+
+	// PerAttempt bounds each individual HTTP attempt (see WithAttemptTimeout)
+	PerAttempt time.Duration
+}
+
+// WithTimeouts configures all client timeouts in one place. Connect,
+// TLSHandshake, ResponseHeader and IdleConn are applied to the transport
+// when New finishes, regardless of option order: to a clone of the default
+// transport, or to a clone of the *http.Transport of a client supplied
+// with WithHTTPClient. Custom http.RoundTripper implementations are left
+// untouched. Overall and PerAttempt are enforced by the SDK itself and
+// always apply; when zero, they leave a timeout set by an earlier option,
+// such as WithAttemptTimeout, in place.
+func WithTimeouts(timeouts TimeoutConfig) ClientOption {
+	return func(c *Client) {
+		c.timeouts = &timeouts
+		if timeouts.Overall > 0 {
+			c.overallTimeout = timeouts.Overall
+		}
+		if timeouts.PerAttempt > 0 {
+			c.attemptTimeout = timeouts.PerAttempt
+		}
+	}
+}
+
+// buildTransport applies the transport-level timeouts to rt. It returns rt
+// unchanged when there is nothing to apply or rt cannot be configured.
+func (t *TimeoutConfig) buildTransport(rt http.RoundTripper) http.RoundTripper {
+	if t == nil || (t.Connect == 0 && t.TLSHandshake == 0 && t.ResponseHeader == 0 && t.IdleConn == 0) {
+		return rt
+	}
+
+	var transport *http.Transport
+	switch base := rt.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = base.Clone()
+	default:
+		return rt
+	}
+
+	if t.Connect > 0 {
+		transport.DialContext = (&net.Dialer{
+			Timeout:   t.Connect,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
+	if t.TLSHandshake > 0 {
+		transport.TLSHandshakeTimeout = t.TLSHandshake
+	}
+	if t.ResponseHeader > 0 {
+		transport.ResponseHeaderTimeout = t.ResponseHeader
+	}
+	if t.IdleConn > 0 {
+		transport.IdleConnTimeout = t.IdleConn
+	}
+
+	return transport
+}
@@ -0,0 +1,56 @@
+package ekdsend
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithTimeoutsTransport(t *testing.T) {
+	client := newTestClient(t, "http://api.ekdsend.test", WithTimeouts(TimeoutConfig{
+		TLSHandshake:   3 * time.Second,
+		ResponseHeader: 4 * time.Second,
+		IdleConn:       5 * time.Second,
+	}))
+
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport is %T, want *http.Transport", client.httpClient.Transport)
+	}
+	if transport.TLSHandshakeTimeout != 3*time.Second {
+		t.Errorf("TLSHandshakeTimeout = %s, want 3s", transport.TLSHandshakeTimeout)
+	}
+	if transport.ResponseHeaderTimeout != 4*time.Second {
+		t.Errorf("ResponseHeaderTimeout = %s, want 4s", transport.ResponseHeaderTimeout)
+	}
+	if transport.IdleConnTimeout != 5*time.Second {
+		t.Errorf("IdleConnTimeout = %s, want 5s", transport.IdleConnTimeout)
+	}
+	if http.DefaultTransport.(*http.Transport).IdleConnTimeout == 5*time.Second {
+		t.Error("default transport was modified")
+	}
+
+	cfg := client.Config()
+	if cfg.TLSHandshakeTimeout != 3*time.Second || cfg.ResponseHeaderTimeout != 4*time.Second || cfg.IdleConnTimeout != 5*time.Second {
+		t.Errorf("Config() timeouts = %s/%s/%s, want 3s/4s/5s",
+			cfg.TLSHandshakeTimeout, cfg.ResponseHeaderTimeout, cfg.IdleConnTimeout)
+	}
+}
+
+func TestWithTimeoutsKeepsEarlierTimeouts(t *testing.T) {
+	client := newTestClient(t, "http://api.ekdsend.test",
+		WithAttemptTimeout(2*time.Second),
+		WithTimeouts(TimeoutConfig{Connect: time.Second}),
+	)
+	if client.attemptTimeout != 2*time.Second {
+		t.Errorf("attemptTimeout = %s, want 2s", client.attemptTimeout)
+	}
+
+	client = newTestClient(t, "http://api.ekdsend.test",
+		WithAttemptTimeout(2*time.Second),
+		WithTimeouts(TimeoutConfig{PerAttempt: 3 * time.Second}),
+	)
+	if client.attemptTimeout != 3*time.Second {
+		t.Errorf("attemptTimeout = %s, want 3s", client.attemptTimeout)
+	}
+}
@@ -1,11 +1,17 @@
 package ekdsend
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
-// Email represents an email object
+// Email represents an email object. TLSVersion reports the transport
+// security negotiated with the recipient's server once the API knows it.
+// QueuePosition and EstimatedSendAt are set for queued and scheduled
+// emails when the API reports them.
 type Email struct {
 	ID          string            `json:"id"`
-	Status      string            `json:"status"`
+	Status      EmailStatus       `json:"status"`
 	From        string            `json:"from"`
 	To          []string          `json:"to"`
 	Subject     string            `json:"subject"`
@@ -16,29 +22,81 @@ type Email struct {
 	ReplyTo     string            `json:"reply_to,omitempty"`
 	Tags        []string          `json:"tags,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
+	RequireTLS  bool              `json:"require_tls,omitempty"`
+	TLSVersion  string            `json:"tls_version,omitempty"`
 	CreatedAt   time.Time         `json:"created_at"`
 	SentAt      *time.Time        `json:"sent_at,omitempty"`
 	DeliveredAt *time.Time        `json:"delivered_at,omitempty"`
+
+	QueuePosition   int        `json:"queue_position,omitempty"`
+	EstimatedSendAt *time.Time `json:"estimated_send_at,omitempty"`
+
+	// Bounce is set when the email bounced
+	Bounce *BounceInfo `json:"bounce,omitempty"`
+
+	// MessageID is the Message-ID header of the outgoing email, for
+	// threading replies with SendEmailParams.ThreadWith
+	MessageID string `json:"message_id,omitempty"`
+
+	// RequestID is the x-request-id of the response this object was read
+	// from, for correlating with server logs in support requests
+	RequestID string `json:"-"`
+}
+
+// BounceInfo describes why an email bounced
+type BounceInfo struct {
+	Type           BounceType `json:"type"`
+	SubType        string     `json:"sub_type,omitempty"`
+	DiagnosticCode string     `json:"diagnostic_code,omitempty"`
+	BouncedAt      time.Time  `json:"bounced_at"`
 }
 
-// SMS represents an SMS message object
+// IsHardBounce reports whether the email bounced permanently, in which case
+// the address should be suppressed
+func (e *Email) IsHardBounce() bool {
+	return e.Bounce != nil && e.Bounce.Type == BounceHard
+}
+
+// requestIDCarrier is implemented by objects that record the request ID of
+// the response they were decoded from
+type requestIDCarrier interface {
+	setRequestID(id string)
+}
+
+func (e *Email) setRequestID(id string)     { e.RequestID = id }
+func (s *SMS) setRequestID(id string)       { s.RequestID = id }
+func (v *VoiceCall) setRequestID(id string) { v.RequestID = id }
+
+// SMS represents an SMS message object. Encoding is the character encoding
+// used for the message (EncodingGSM7 or EncodingUCS2); when the API does
+// not report it, it is derived from the message content. QueuePosition and
+// EstimatedSendAt are set for queued and scheduled messages when the API
+// reports them.
 type SMS struct {
 	ID          string            `json:"id"`
-	Status      string            `json:"status"`
+	Status      SMSStatus         `json:"status"`
 	To          string            `json:"to"`
 	From        string            `json:"from,omitempty"`
 	Message     string            `json:"message"`
 	Segments    int               `json:"segments"`
+	Encoding    string            `json:"encoding,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
 	CreatedAt   time.Time         `json:"created_at"`
 	SentAt      *time.Time        `json:"sent_at,omitempty"`
 	DeliveredAt *time.Time        `json:"delivered_at,omitempty"`
+
+	QueuePosition   int        `json:"queue_position,omitempty"`
+	EstimatedSendAt *time.Time `json:"estimated_send_at,omitempty"`
+
+	// RequestID is the x-request-id of the response this object was read
+	// from, for correlating with server logs in support requests
+	RequestID string `json:"-"`
 }
 
 // VoiceCall represents a voice call object
 type VoiceCall struct {
 	ID               string            `json:"id"`
-	Status           string            `json:"status"`
+	Status           CallStatus        `json:"status"`
 	To               string            `json:"to"`
 	From             string            `json:"from"`
 	TTSMessage       string            `json:"tts_message,omitempty"`
@@ -53,6 +111,14 @@ type VoiceCall struct {
 	CreatedAt        time.Time         `json:"created_at"`
 	AnsweredAt       *time.Time        `json:"answered_at,omitempty"`
 	EndedAt          *time.Time        `json:"ended_at,omitempty"`
+
+	// DigitsPressed holds the keypad input collected by
+	// CreateCallParams.Gather, once the call has completed
+	DigitsPressed string `json:"digits_pressed,omitempty"`
+
+	// RequestID is the x-request-id of the response this object was read
+	// from; see Email.RequestID
+	RequestID string `json:"-"`
 }
 
 // Recording represents a call recording
@@ -62,11 +128,120 @@ type Recording struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-// Attachment represents an email attachment
+// TrackedLink is a link rewritten for click tracking in an email
+type TrackedLink struct {
+	OriginalURL string `json:"original_url"`
+	TrackingURL string `json:"tracking_url"`
+	Clicks      int    `json:"clicks"`
+}
+
+// KeyInfo describes the API key a client authenticates with
+type KeyInfo struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	Mode      string    `json:"mode"`
+	Scopes    []string  `json:"scopes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Reputation holds the sending reputation of an account
+type Reputation struct {
+	Domains   []ReputationMetrics `json:"domains"`
+	IPs       []ReputationMetrics `json:"ips"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// ReputationMetrics are the reputation metrics of a single sending domain or IP
+type ReputationMetrics struct {
+	Name          string   `json:"name"`
+	Score         float64  `json:"score"`
+	BounceRate    float64  `json:"bounce_rate"`
+	ComplaintRate float64  `json:"complaint_rate"`
+	Blocklisted   bool     `json:"blocklisted"`
+	Blocklists    []string `json:"blocklists,omitempty"`
+}
+
+// Engagement is a recipient's open and click history
+type Engagement struct {
+	Email         string     `json:"email"`
+	LastOpenedAt  *time.Time `json:"last_opened_at,omitempty"`
+	LastClickedAt *time.Time `json:"last_clicked_at,omitempty"`
+	TotalOpens    int        `json:"total_opens"`
+	TotalClicks   int        `json:"total_clicks"`
+
+	// Tier is computed by the client from LastEngagedAt
+	Tier EngagementTier `json:"-"`
+}
+
+// Template is a server-side email template. Variables lists the names the
+// template expects in its data.
+type Template struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Subject   string    `json:"subject"`
+	HTML      string    `json:"html,omitempty"`
+	Text      string    `json:"text,omitempty"`
+	Variables []string  `json:"variables"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Attachment disposition values
+const (
+	DispositionAttachment = "attachment"
+	DispositionInline     = "inline"
+)
+
+// Attachment represents an email attachment. An inline attachment is
+// displayed within the HTML body, where it is referenced by its ContentID
+// as <img src="cid:logo">.
 type Attachment struct {
 	Filename    string `json:"filename"`
 	Content     string `json:"content"`
 	ContentType string `json:"content_type,omitempty"`
+	ContentID   string `json:"content_id,omitempty"`
+	Disposition string `json:"disposition,omitempty"`
+}
+
+// batchItem is a single entry of a batch endpoint response. Index refers
+// to the position of the item in the request.
+type batchItem[T any] struct {
+	Index int             `json:"index"`
+	Data  *T              `json:"data,omitempty"`
+	Error *batchItemError `json:"error,omitempty"`
+
+	// Suppressed lists recipients removed by the suppression filter
+	Suppressed []string `json:"-"`
+}
+
+// batchItemError describes why a single batch item was rejected
+type batchItemError struct {
+	Message    string                 `json:"message"`
+	Code       string                 `json:"code"`
+	StatusCode int                    `json:"status_code"`
+	Details    map[string]interface{} `json:"details"`
+	RetryAfter int                    `json:"retry_after"`
+
+	// requestID is the ID of the batch request that carried the item
+	requestID string
+
+	// local is the error of an item rejected before sending, such as a
+	// *ValidationError or a *SuppressedError
+	local error
+}
+
+// err returns the typed error for the item, classified by status code as
+// for a single request
+func (e *batchItemError) err() error {
+	if e.local != nil {
+		return e.local
+	}
+	return classifyError(EKDSendError{
+		Message:    e.Message,
+		StatusCode: e.StatusCode,
+		Code:       e.Code,
+		RequestID:  e.requestID,
+	}, e.Details, e.RetryAfter)
 }
 
 // PaginatedResponse is a generic paginated response
@@ -75,10 +250,20 @@ type PaginatedResponse[T any] struct {
 	Total  int `json:"total"`
 	Limit  int `json:"limit"`
 	Offset int `json:"offset"`
+
+	// NextCursor is the server-issued token for the following page, empty
+	// on the last page or when the endpoint pages by offset only
+	NextCursor string `json:"next_cursor,omitempty"`
+
+	// next fetches the following page; nil on the last page
+	next func(context.Context) (*PaginatedResponse[T], error)
 }
 
 // HasMore returns true if there are more pages
 func (p *PaginatedResponse[T]) HasMore() bool {
+	if p.NextCursor != "" {
+		return true
+	}
 	return (p.Offset + p.Limit) < p.Total
 }
 
@@ -86,3 +271,44 @@ func (p *PaginatedResponse[T]) HasMore() bool {
 func (p *PaginatedResponse[T]) NextOffset() int {
 	return p.Offset + p.Limit
 }
+
+// Next fetches the page after p. It returns false when p is the last page,
+// or when p was not returned by a List method. Pages with a NextCursor
+// continue from it; pages listed with a Before cursor continue from
+// LastCursor; pages listed with an After cursor are not chained.
+func (p *PaginatedResponse[T]) Next(ctx context.Context) (*PaginatedResponse[T], bool, error) {
+	if p.next == nil {
+		return nil, false, nil
+	}
+
+	page, err := p.next(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	return page, true, nil
+}
+
+// bindNext sets up Next for a page listed with the given cursors. fetch
+// lists the page at offset, or before the cursor when it is non-empty.
+func (p *PaginatedResponse[T]) bindNext(before, after string, fetch func(ctx context.Context, offset int, before string) (*PaginatedResponse[T], error)) {
+	switch {
+	case after != "":
+		return
+	case before != "":
+		if len(p.Data) == 0 || len(p.Data) < p.Limit {
+			return
+		}
+		cursor := p.LastCursor()
+		p.next = func(ctx context.Context) (*PaginatedResponse[T], error) {
+			return fetch(ctx, 0, cursor)
+		}
+	default:
+		if !p.HasMore() {
+			return
+		}
+		offset := p.NextOffset()
+		p.next = func(ctx context.Context) (*PaginatedResponse[T], error) {
+			return fetch(ctx, offset, "")
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package ekdsend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithUserAgentSuffix appends suffix, e.g. "billing-service/2.1", to the
+// User-Agent header so EKDSend support can identify the calling service.
+// Characters that are not allowed in a header token, including spaces,
+// are replaced with "-".
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.userAgentSuffix = sanitizeUserAgentToken(suffix)
+	}
+}
+
+// userAgent returns the User-Agent header sent with every request
+func (c *Client) userAgent() string {
+	if c.userAgentSuffix == "" {
+		return fmt.Sprintf("ekdsend-go/%s", Version)
+	}
+	return fmt.Sprintf("ekdsend-go/%s %s", Version, c.userAgentSuffix)
+}
+
+// sanitizeUserAgentToken reduces s to a single User-Agent product token:
+// RFC 9110 token characters, with "/" separating the version
+func sanitizeUserAgentToken(s string) string {
+	token := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("!#$%&'*+-.^_`|~/", r):
+			return r
+		default:
+			return '-'
+		}
+	}, strings.TrimSpace(s))
+	return strings.Trim(token, "-/")
+}
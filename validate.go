@@ -0,0 +1,102 @@
+package ekdsend
+
+import (
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+)
+
+// WithoutClientValidation disables the local checks run before a send, so
+// params are passed through to the API unchanged and only validated
+// server-side
+func WithoutClientValidation() ClientOption {
+	return func(c *Client) {
+		c.skipValidation = true
+	}
+}
+
+// Validate checks params locally: From and every To, CC, BCC and reply-to
+// address must parse, at least one recipient and a Subject are required, one of
+// HTML or Text must be set, and attachments must have content, with a
+// ContentID when inline. It returns a *ValidationError whose Errors map
+// each invalid field, e.g. "to[1]", to a message. Every email send path
+// (Send, SendMultipart, BatchSend, SendThrottled and BatchingSender) calls
+// it after NormalizeRecipients before sending unless the client was created with
+// WithoutClientValidation.
+func (p *SendEmailParams) Validate() error {
+	errs := map[string]interface{}{}
+
+	if p.From == "" {
+		errs["from"] = "required"
+	} else if _, err := mail.ParseAddress(p.From); err != nil {
+		errs["from"] = fmt.Sprintf("invalid address %q", p.From)
+	}
+
+	if len(p.To) == 0 {
+		errs["to"] = "at least one recipient is required"
+	}
+	validateAddresses(errs, "to", p.To)
+	validateAddresses(errs, "cc", p.CC)
+	validateAddresses(errs, "bcc", p.BCC)
+	validateReplyTo(errs, p.ReplyTo, p.ReplyToList)
+
+	if strings.TrimSpace(p.Subject) == "" {
+		errs["subject"] = "required"
+	}
+	if p.HTML == "" && p.Text == "" {
+		errs["html"] = "one of HTML or Text is required"
+	}
+
+	for i, attachment := range p.Attachments {
+		if attachment.Content == "" {
+			errs[fmt.Sprintf("attachments[%d].content", i)] = "empty attachment"
+		}
+		switch attachment.Disposition {
+		case "", DispositionAttachment:
+		case DispositionInline:
+			if attachment.ContentID == "" {
+				errs[fmt.Sprintf("attachments[%d].content_id", i)] = "required for inline attachments"
+			}
+		default:
+			errs[fmt.Sprintf("attachments[%d].disposition", i)] = fmt.Sprintf("must be %q or %q", DispositionInline, DispositionAttachment)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	fields := make([]string, 0, len(errs))
+	for field := range errs {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return newValidationError("invalid email params: "+strings.Join(fields, ", "), errs)
+}
+
+// validateAddresses records an error for each address in list that does
+// not parse
+func validateAddresses(errs map[string]interface{}, field string, list []string) {
+	for i, address := range list {
+		if _, err := mail.ParseAddress(address); err != nil {
+			errs[fmt.Sprintf("%s[%d]", field, i)] = fmt.Sprintf("invalid address %q", address)
+		}
+	}
+}
+
+// validateReplyTo records an error for each ReplyToList address that does
+// not parse or, when the list is empty, for a ReplyTo that is not a valid
+// address list. ReplyTo is ignored when ReplyToList is set, as it is when
+// sending.
+func validateReplyTo(errs map[string]interface{}, replyTo string, list []string) {
+	if len(list) > 0 {
+		validateAddresses(errs, "reply_to_list", list)
+		return
+	}
+	if replyTo != "" {
+		if _, err := mail.ParseAddressList(replyTo); err != nil {
+			errs["reply_to"] = fmt.Sprintf("invalid address list %q", replyTo)
+		}
+	}
+}
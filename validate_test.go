@@ -0,0 +1,122 @@
+package ekdsend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// countingServer answers email sends and counts the emails it received
+func countingServer(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+
+	var received int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/emails/batch" {
+			received += writeBatch(w, r)
+			return
+		}
+		received++
+		writeEmail(w, "em_1")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &received
+}
+
+func TestBatchSendValidatesItems(t *testing.T) {
+	srv, received := countingServer(t)
+	client := newTestClient(t, srv.URL)
+
+	invalid := testEmailParams()
+	invalid.Subject = ""
+
+	result, err := client.Emails.BatchSend(context.Background(), []*SendEmailParams{testEmailParams(), invalid, testEmailParams()})
+	if err != nil {
+		t.Fatalf("BatchSend: %v", err)
+	}
+	if len(result.Succeeded) != 2 || len(result.Failed) != 1 {
+		t.Fatalf("got %d succeeded and %d failed, want 2 and 1", len(result.Succeeded), len(result.Failed))
+	}
+	failed := result.Failed[0]
+	if failed.Index != 1 || !IsValidationError(failed.Err) {
+		t.Errorf("failed item = %d %v, want 1 with a ValidationError", failed.Index, failed.Err)
+	}
+	if *received != 2 {
+		t.Errorf("API received %d emails, want 2", *received)
+	}
+}
+
+func TestSendMultipartValidates(t *testing.T) {
+	srv, received := countingServer(t)
+	client := newTestClient(t, srv.URL)
+
+	params := testEmailParams()
+	params.To = nil
+
+	_, err := client.Emails.SendMultipart(context.Background(), params, nil)
+	if !IsValidationError(err) {
+		t.Errorf("SendMultipart error = %v, want a ValidationError", err)
+	}
+	if *received != 0 {
+		t.Errorf("API received %d requests, want 0", *received)
+	}
+}
+
+func TestValidateReplyTo(t *testing.T) {
+	tests := []struct {
+		replyTo string
+		list    []string
+		field   string
+	}{
+		{replyTo: "Support <support@example.com>, sales@example.com"},
+		{replyTo: "not an address", field: "reply_to"},
+		{list: []string{"alice@example.com", "bob"}, field: "reply_to_list[1]"},
+		// ReplyToList wins, so an invalid ReplyTo is not sent or checked
+		{replyTo: "not an address", list: []string{"alice@example.com"}},
+	}
+	for _, tt := range tests {
+		email := testEmailParams()
+		email.ReplyTo, email.ReplyToList = tt.replyTo, tt.list
+		template := &SendTemplateParams{TemplateID: "tpl_1", To: []string{"user@example.com"}, ReplyTo: tt.replyTo, ReplyToList: tt.list}
+
+		for name, err := range map[string]error{"SendEmailParams": email.Validate(), "SendTemplateParams": template.Validate()} {
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("%s.Validate() with ReplyTo %q, ReplyToList %q = %v, want nil", name, tt.replyTo, tt.list, err)
+				}
+				continue
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Errors[tt.field] == nil {
+				t.Errorf("%s.Validate() with ReplyTo %q, ReplyToList %q = %v, want an error for %s", name, tt.replyTo, tt.list, err, tt.field)
+			}
+		}
+	}
+}
+
+func TestSendTemplateParamsReplyToList(t *testing.T) {
+	params := SendTemplateParams{
+		TemplateID:  "tpl_1",
+		To:          []string{"user@example.com"},
+		ReplyTo:     "ignored@example.com",
+		ReplyToList: []string{"alice@example.com", "bob@example.com"},
+	}
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := "alice@example.com, bob@example.com"; got["reply_to"] != want {
+		t.Errorf("reply_to = %v, want %q", got["reply_to"], want)
+	}
+	if _, ok := got["ReplyToList"]; ok {
+		t.Error("ReplyToList was encoded as its own field")
+	}
+}
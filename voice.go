@@ -4,10 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
+// maxCallBatchSize is the largest number of calls accepted by the batch
+// endpoint
+const maxCallBatchSize = 100
+
 // VoiceAPI provides access to the Voice API
 type VoiceAPI struct {
 	client *Client
@@ -19,12 +25,44 @@ type CreateCallParams struct {
 	From             string            `json:"from"`
 	TTSMessage       string            `json:"tts_message,omitempty"`
 	AudioURL         string            `json:"audio_url,omitempty"`
-	Voice            string            `json:"voice,omitempty"`
-	Language         string            `json:"language,omitempty"`
+	Voice            Voice             `json:"voice,omitempty"`
+	Language         Language          `json:"language,omitempty"`
 	Record           bool              `json:"record,omitempty"`
 	MachineDetection bool              `json:"machine_detection,omitempty"`
 	WebhookURL       string            `json:"webhook_url,omitempty"`
 	Metadata         map[string]string `json:"metadata,omitempty"`
+
+	// Gather collects keypad input after the message has played
+	Gather *GatherOptions `json:"gather,omitempty"`
+
+	// IdempotencyKey is sent as the Idempotency-Key header; see
+	// WithIdempotencyKey
+	IdempotencyKey string `json:"-"`
+}
+
+// GatherOptions configure the collection of keypad (DTMF) input during a
+// call. Input ends after NumDigits digits, when FinishOnKey is pressed or
+// after Timeout seconds without a key press, whichever comes first. The
+// digits are posted to WebhookURL and reported in VoiceCall.DigitsPressed.
+type GatherOptions struct {
+	NumDigits   int    `json:"num_digits,omitempty"`
+	Timeout     int    `json:"timeout,omitempty"`
+	FinishOnKey string `json:"finish_on_key,omitempty"`
+	WebhookURL  string `json:"webhook_url,omitempty"`
+}
+
+// validate checks the options locally
+func (g *GatherOptions) validate() error {
+	if g.NumDigits < 0 {
+		return newValidationError("gather num_digits must not be negative", map[string]interface{}{"gather.num_digits": g.NumDigits})
+	}
+	if g.Timeout < 0 {
+		return newValidationError("gather timeout must not be negative", map[string]interface{}{"gather.timeout": g.Timeout})
+	}
+	if g.FinishOnKey != "" && (len(g.FinishOnKey) != 1 || !strings.Contains("0123456789*#", g.FinishOnKey)) {
+		return newValidationError(fmt.Sprintf("invalid gather finish_on_key %q", g.FinishOnKey), map[string]interface{}{"gather.finish_on_key": "must be a single digit, * or #"})
+	}
+	return nil
 }
 
 // ListCallsParams are the parameters for listing calls
@@ -34,46 +72,208 @@ type ListCallsParams struct {
 	Status   string
 	FromDate string
 	ToDate   string
+	Metadata map[string]string
+
+	// Before and After are cursor tokens from PaginatedResponse.LastCursor
+	// and FirstCursor. When either is set, records are paged by creation
+	// time and ID instead of Offset.
+	Before string
+	After  string
 }
 
 // Create creates a new voice call
-func (v *VoiceAPI) Create(ctx context.Context, params *CreateCallParams) (*VoiceCall, error) {
+func (v *VoiceAPI) Create(ctx context.Context, params *CreateCallParams, opts ...RequestOption) (*VoiceCall, error) {
+	if err := v.client.checkTestMode(); err != nil {
+		return nil, err
+	}
 	if params.TTSMessage == "" && params.AudioURL == "" {
 		return nil, errors.New("either TTSMessage or AudioURL is required")
 	}
+	if !v.client.skipValidation {
+		if err := validatePhoneField("to", params.To); err != nil {
+			return nil, err
+		}
+		if err := validatePhoneField("from", params.From); err != nil {
+			return nil, err
+		}
+		if params.Gather != nil {
+			if err := params.Gather.validate(); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	// Set defaults
-	if params.Voice == "" {
-		params.Voice = "alloy"
+	params = v.prepare(params)
+
+	if !v.client.skipValidation {
+		if err := validateVoice(params.Voice, params.Language); err != nil {
+			return nil, err
+		}
 	}
-	if params.Language == "" {
-		params.Language = "en-US"
+
+	if v.client.simulate {
+		call := v.client.simulateCall(params)
+		v.client.sent("calls", []string{params.To}, call.ID, string(call.Status), nil, nil)
+		return call, nil
 	}
 
-	var resp struct {
-		Data VoiceCall `json:"data"`
+	var resp VoiceCall
+	var meta ResponseMeta
+
+	err := v.client.Post(ctx, "/calls", params, envelope(&resp), withMeta(&meta, withParamsKey(params.IdempotencyKey, opts))...)
+	v.client.sent("calls", []string{params.To}, resp.ID, string(resp.Status), &meta, err)
+	if err != nil {
+		return nil, err
 	}
 
-	err := v.client.Post(ctx, "/calls", params, &resp)
+	return &resp, nil
+}
+
+// CreateBatch places the call described by common to every number in to,
+// through the batch endpoint, up to 100 calls per request. common.To is
+// ignored; the same defaults as Create are applied. Rejected calls are
+// returned in Failed, indexed by their position in to; an error is
+// returned only when a request as a whole fails, together with the results
+// collected before it.
+func (v *VoiceAPI) CreateBatch(ctx context.Context, common *CreateCallParams, to []string, opts ...BatchOption) (*BatchResult[VoiceCall], error) {
+	if err := v.client.checkTestMode(); err != nil {
+		return nil, err
+	}
+	if common.TTSMessage == "" && common.AudioURL == "" {
+		return nil, errors.New("either TTSMessage or AudioURL is required")
+	}
+	if len(to) == 0 {
+		return nil, newValidationError("at least one recipient is required", map[string]interface{}{"to": "required"})
+	}
+	for i, number := range to {
+		if strings.TrimSpace(number) == "" {
+			return nil, newValidationError("recipient is empty", map[string]interface{}{fmt.Sprintf("to[%d]", i): "required"})
+		}
+		if !v.client.skipValidation {
+			if err := validatePhoneField(fmt.Sprintf("to[%d]", i), number); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !v.client.skipValidation {
+		if err := validatePhoneField("from", common.From); err != nil {
+			return nil, err
+		}
+		if common.Gather != nil {
+			if err := common.Gather.validate(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	options := newBatchOptions(opts)
+	result := &BatchResult[VoiceCall]{}
+	common = v.prepare(common)
+	if !v.client.skipValidation {
+		if err := validateVoice(common.Voice, common.Language); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < len(to); i += maxCallBatchSize {
+		end := i + maxCallBatchSize
+		if end > len(to) {
+			end = len(to)
+		}
+
+		calls := make([]*CreateCallParams, 0, end-i)
+		for _, number := range to[i:end] {
+			p := *common
+			p.To = number
+			calls = append(calls, &p)
+		}
+
+		results, err := v.postBatch(ctx, calls, options.chunk(i))
+		if err != nil {
+			return result, err
+		}
+
+		result.add(results, i, end-i)
+		if options.failFast && len(result.Failed) > 0 {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// postBatch sends prepared calls to the batch endpoint
+func (v *VoiceAPI) postBatch(ctx context.Context, calls []*CreateCallParams, options batchOptions) ([]batchItem[VoiceCall], error) {
+	body := struct {
+		Calls    []*CreateCallParams `json:"calls"`
+		FailFast bool                `json:"fail_fast,omitempty"`
+	}{
+		Calls:    calls,
+		FailFast: options.failFast,
+	}
+
+	if v.client.simulate {
+		results := make([]batchItem[VoiceCall], len(calls))
+		for i, params := range calls {
+			call := v.client.simulateCall(params)
+			v.client.sent("calls", []string{params.To}, call.ID, string(call.Status), nil, nil)
+			results[i] = batchItem[VoiceCall]{Index: i, Data: call}
+		}
+		return results, nil
+	}
+
+	var resp []batchItem[VoiceCall]
+	var meta ResponseMeta
+
+	err := v.client.Post(ctx, "/calls/batch", body, envelope(&resp), options.requestOptions(&meta)...)
 	if err != nil {
+		for _, params := range calls {
+			v.client.sent("calls", []string{params.To}, "", "", &meta, err)
+		}
 		return nil, err
 	}
 
-	return &resp.Data, nil
+	for _, res := range resp {
+		if res.Index < 0 || res.Index >= len(calls) {
+			continue
+		}
+		to := []string{calls[res.Index].To}
+		switch {
+		case res.Error != nil:
+			res.Error.requestID = meta.RequestID
+			v.client.sent("calls", to, "", "", &meta, res.Error.err())
+		case res.Data != nil:
+			v.client.sent("calls", to, res.Data.ID, string(res.Data.Status), &meta, nil)
+		}
+	}
+
+	return resp, nil
 }
 
-// Get retrieves a call by ID
-func (v *VoiceAPI) Get(ctx context.Context, callID string) (*VoiceCall, error) {
-	var resp struct {
-		Data VoiceCall `json:"data"`
+// prepare applies the default voice, language and metadata to a copy of
+// params, so callers can share params across goroutines
+func (v *VoiceAPI) prepare(params *CreateCallParams) *CreateCallParams {
+	p := *params
+	p.Metadata = v.client.withDefaultMetadata(p.Metadata)
+	if p.Voice == "" {
+		p.Voice = VoiceAlloy
+	}
+	if p.Language == "" {
+		p.Language = LanguageEnglishUS
 	}
+	return &p
+}
+
+// Get retrieves a call by ID
+func (v *VoiceAPI) Get(ctx context.Context, callID string, opts ...RequestOption) (*VoiceCall, error) {
+	var resp VoiceCall
 
-	err := v.client.Get(ctx, fmt.Sprintf("/calls/%s", callID), nil, &resp)
+	err := v.client.Get(ctx, fmt.Sprintf("/calls/%s", callID), nil, envelope(&resp), opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &resp.Data, nil
+	return &resp, nil
 }
 
 // List retrieves a paginated list of calls
@@ -84,7 +284,9 @@ func (v *VoiceAPI) List(ctx context.Context, params *ListCallsParams) (*Paginate
 
 	query := url.Values{}
 	query.Set("limit", strconv.Itoa(params.Limit))
-	query.Set("offset", strconv.Itoa(params.Offset))
+	if err := setPageQuery(query, params.Offset, params.Before, params.After); err != nil {
+		return nil, err
+	}
 
 	if params.Status != "" {
 		query.Set("status", params.Status)
@@ -95,6 +297,7 @@ func (v *VoiceAPI) List(ctx context.Context, params *ListCallsParams) (*Paginate
 	if params.ToDate != "" {
 		query.Set("to_date", params.ToDate)
 	}
+	setMetadataQuery(query, params.Metadata)
 
 	var resp PaginatedResponse[VoiceCall]
 	err := v.client.Get(ctx, "/calls", query, &resp)
@@ -102,33 +305,45 @@ func (v *VoiceAPI) List(ctx context.Context, params *ListCallsParams) (*Paginate
 		return nil, err
 	}
 
+	resp.bindNext(params.Before, params.After, func(ctx context.Context, offset int, before string) (*PaginatedResponse[VoiceCall], error) {
+		next := *params
+		next.Offset, next.Before = offset, before
+		return v.List(ctx, &next)
+	})
+
 	return &resp, nil
 }
 
 // Hangup hangs up an active call
 func (v *VoiceAPI) Hangup(ctx context.Context, callID string) (*VoiceCall, error) {
-	var resp struct {
-		Data VoiceCall `json:"data"`
-	}
+	var resp VoiceCall
 
-	err := v.client.Delete(ctx, fmt.Sprintf("/calls/%s", callID), &resp)
+	err := v.client.Delete(ctx, fmt.Sprintf("/calls/%s", callID), envelope(&resp))
 	if err != nil {
 		return nil, err
 	}
 
-	return &resp.Data, nil
+	return &resp, nil
 }
 
 // GetRecording retrieves the recording for a call
 func (v *VoiceAPI) GetRecording(ctx context.Context, callID string) (*Recording, error) {
-	var resp struct {
-		Data Recording `json:"data"`
-	}
+	var resp Recording
 
-	err := v.client.Get(ctx, fmt.Sprintf("/calls/%s/recording", callID), nil, &resp)
+	err := v.client.Get(ctx, fmt.Sprintf("/calls/%s/recording", callID), nil, envelope(&resp))
 	if err != nil {
 		return nil, err
 	}
 
-	return &resp.Data, nil
+	return &resp, nil
+}
+
+// DownloadRecording streams the audio of a call's recording to w and
+// returns the number of bytes written. The recording is fetched with the
+// client's credentials and copied as it arrives, so large recordings are
+// never held in memory. The download is not retried; use
+// WithRequestTimeout to allow more than the client's timeout for large
+// files.
+func (v *VoiceAPI) DownloadRecording(ctx context.Context, callID string, w io.Writer, opts ...RequestOption) (int64, error) {
+	return v.client.download(ctx, fmt.Sprintf("/calls/%s/recording/download", url.PathEscape(callID)), w, opts...)
 }
@@ -0,0 +1,84 @@
+package ekdsend
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Voice is a text-to-speech voice
+type Voice string
+
+// Supported voices
+const (
+	VoiceAlloy   Voice = "alloy"
+	VoiceEcho    Voice = "echo"
+	VoiceFable   Voice = "fable"
+	VoiceOnyx    Voice = "onyx"
+	VoiceNova    Voice = "nova"
+	VoiceShimmer Voice = "shimmer"
+)
+
+// Language is a BCP 47 language tag for text-to-speech
+type Language string
+
+// Supported languages
+const (
+	LanguageEnglishUS    Language = "en-US"
+	LanguageEnglishGB    Language = "en-GB"
+	LanguageEnglishAU    Language = "en-AU"
+	LanguageSpanishES    Language = "es-ES"
+	LanguageSpanishMX    Language = "es-MX"
+	LanguageFrenchFR     Language = "fr-FR"
+	LanguageFrenchCA     Language = "fr-CA"
+	LanguageGermanDE     Language = "de-DE"
+	LanguageItalianIT    Language = "it-IT"
+	LanguagePortugueseBR Language = "pt-BR"
+	LanguagePortuguesePT Language = "pt-PT"
+	LanguageDutchNL      Language = "nl-NL"
+	LanguageJapaneseJP   Language = "ja-JP"
+	LanguageKoreanKR     Language = "ko-KR"
+	LanguageChineseCN    Language = "zh-CN"
+	LanguageHindiIN      Language = "hi-IN"
+	LanguageArabicSA     Language = "ar-SA"
+	LanguageSwahiliKE    Language = "sw-KE"
+)
+
+var supportedVoices = []Voice{VoiceAlloy, VoiceEcho, VoiceFable, VoiceOnyx, VoiceNova, VoiceShimmer}
+
+var supportedLanguages = []Language{
+	LanguageEnglishUS, LanguageEnglishGB, LanguageEnglishAU,
+	LanguageSpanishES, LanguageSpanishMX, LanguageFrenchFR, LanguageFrenchCA,
+	LanguageGermanDE, LanguageItalianIT, LanguagePortugueseBR, LanguagePortuguesePT,
+	LanguageDutchNL, LanguageJapaneseJP, LanguageKoreanKR, LanguageChineseCN,
+	LanguageHindiIN, LanguageArabicSA, LanguageSwahiliKE,
+}
+
+// SupportedVoices returns the voices accepted by Calls.Create
+func SupportedVoices() []Voice {
+	return append([]Voice(nil), supportedVoices...)
+}
+
+// SupportedLanguages returns the languages accepted by Calls.Create
+func SupportedLanguages() []Language {
+	return append([]Language(nil), supportedLanguages...)
+}
+
+// validateVoice checks the voice and language of prepared call params
+func validateVoice(voice Voice, language Language) error {
+	if !slices.Contains(supportedVoices, voice) {
+		return newValidationError(fmt.Sprintf("unsupported voice %q", voice), map[string]interface{}{"voice": "must be one of " + joinValues(supportedVoices)})
+	}
+	if !slices.Contains(supportedLanguages, language) {
+		return newValidationError(fmt.Sprintf("unsupported language %q", language), map[string]interface{}{"language": "must be one of " + joinValues(supportedLanguages)})
+	}
+	return nil
+}
+
+func joinValues[T ~string](values []T) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = string(v)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,141 @@
+package ekdsend
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxWebhookBodySize bounds the webhook payloads WebhookHandler reads
+const maxWebhookBodySize = 1 << 20
+
+// WebhookHandler is an http.Handler for a webhook endpoint. It verifies
+// each request's signature, parses the event and calls the callbacks
+// registered for its type. Register callbacks before serving requests.
+type WebhookHandler struct {
+	secret    string
+	tolerance time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string][]func(*WebhookEvent)
+
+	// OnError, when set, is called with requests that fail verification
+	// or parsing, e.g. for logging
+	OnError func(r *http.Request, err error)
+}
+
+// NewWebhookHandler creates a WebhookHandler that verifies requests with
+// the endpoint's signing secret. With an empty secret, e.g. from an unset
+// environment variable, every request is rejected.
+func NewWebhookHandler(secret string) *WebhookHandler {
+	return &WebhookHandler{
+		secret:    secret,
+		tolerance: DefaultWebhookTolerance,
+		handlers:  make(map[string][]func(*WebhookEvent)),
+	}
+}
+
+// WithTolerance sets the accepted signature timestamp skew; zero disables
+// the check. It returns h for chaining.
+func (h *WebhookHandler) WithTolerance(tolerance time.Duration) *WebhookHandler {
+	h.tolerance = tolerance
+	return h
+}
+
+// On registers fn for events of the given type
+func (h *WebhookHandler) On(eventType string, fn func(*WebhookEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[eventType] = append(h.handlers[eventType], fn)
+}
+
+// OnEmailDelivered registers fn for email.delivered events
+func (h *WebhookHandler) OnEmailDelivered(fn func(*Email)) {
+	h.onEmail(EventEmailDelivered, fn)
+}
+
+// OnEmailBounced registers fn for email.bounced events
+func (h *WebhookHandler) OnEmailBounced(fn func(*Email)) {
+	h.onEmail(EventEmailBounced, fn)
+}
+
+// OnSMSDelivered registers fn for sms.delivered events
+func (h *WebhookHandler) OnSMSDelivered(fn func(*SMS)) {
+	h.onSMS(EventSMSDelivered, fn)
+}
+
+// OnSMSFailed registers fn for sms.failed events
+func (h *WebhookHandler) OnSMSFailed(fn func(*SMS)) {
+	h.onSMS(EventSMSFailed, fn)
+}
+
+// OnCallCompleted registers fn for call.completed events
+func (h *WebhookHandler) OnCallCompleted(fn func(*VoiceCall)) {
+	h.On(EventCallCompleted, func(event *WebhookEvent) {
+		if call, ok := event.AsCall(); ok {
+			fn(call)
+		}
+	})
+}
+
+func (h *WebhookHandler) onEmail(eventType string, fn func(*Email)) {
+	h.On(eventType, func(event *WebhookEvent) {
+		if email, ok := event.AsEmail(); ok {
+			fn(email)
+		}
+	})
+}
+
+func (h *WebhookHandler) onSMS(eventType string, fn func(*SMS)) {
+	h.On(eventType, func(event *WebhookEvent) {
+		if sms, ok := event.AsSMS(); ok {
+			fn(sms)
+		}
+	})
+}
+
+// ServeHTTP verifies, parses and dispatches a webhook request. It answers
+// 401 for a bad signature, 400 for an unparseable payload, and 200 once
+// the callbacks have returned, including for event types with none.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		h.fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	err = VerifySignatureWithTolerance(payload, r.Header.Get("X-EKDSend-Signature"), h.secret, h.tolerance)
+	if err != nil {
+		h.fail(w, r, err, http.StatusUnauthorized)
+		return
+	}
+
+	event, err := ParseWebhookEvent(payload)
+	if err != nil {
+		h.fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	h.mu.RLock()
+	handlers := h.handlers[event.Type]
+	h.mu.RUnlock()
+
+	for _, fn := range handlers {
+		fn(event)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) fail(w http.ResponseWriter, r *http.Request, err error, status int) {
+	if h.OnError != nil {
+		h.OnError(r, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
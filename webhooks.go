@@ -0,0 +1,273 @@
+package ekdsend
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultWebhookTolerance is how old a signed webhook timestamp may be
+// before VerifySignature rejects it as a possible replay
+const DefaultWebhookTolerance = 5 * time.Minute
+
+var (
+	// ErrEventFiltered is returned by ParseWebhookEventFiltered for events
+	// whose type is not in the allowed list
+	ErrEventFiltered = errors.New("ekdsend: webhook event type filtered")
+
+	// ErrInvalidSignature is returned when a webhook signature header is
+	// malformed or does not match the payload
+	ErrInvalidSignature = errors.New("ekdsend: invalid webhook signature")
+
+	// ErrSignatureExpired is returned when a webhook signature's timestamp
+	// is outside the tolerance
+	ErrSignatureExpired = errors.New("ekdsend: webhook signature timestamp outside tolerance")
+)
+
+// Webhook event types
+const (
+	EventEmailSent       = "email.sent"
+	EventEmailDelivered  = "email.delivered"
+	EventEmailBounced    = "email.bounced"
+	EventEmailComplained = "email.complained"
+	EventEmailFailed     = "email.failed"
+	EventSMSSent         = "sms.sent"
+	EventSMSDelivered    = "sms.delivered"
+	EventSMSFailed       = "sms.failed"
+	EventCallCompleted   = "call.completed"
+	EventCallFailed      = "call.failed"
+)
+
+// BounceType classifies a bounce
+type BounceType string
+
+// Bounce types
+const (
+	// BounceHard is a permanent failure, such as an unknown mailbox; the
+	// address should not be mailed again
+	BounceHard BounceType = "hard"
+
+	// BounceSoft is a temporary failure, such as a full mailbox
+	BounceSoft BounceType = "soft"
+)
+
+// WebhookEvent is an event delivered to a webhook endpoint
+type WebhookEvent struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+
+	// Bounce is set by ParseWebhookEvent for email.bounced events
+	Bounce *BounceEvent `json:"-"`
+}
+
+// BounceEvent is the payload of an email.bounced event. The bounce details
+// are the same BounceInfo that Email.Bounce carries.
+type BounceEvent struct {
+	EmailID   string `json:"email_id"`
+	Recipient string `json:"recipient"`
+	SMTPCode  int    `json:"smtp_code"`
+	BounceInfo
+}
+
+// parseBounce decodes the bounce details of an email.bounced event. Details
+// nested under "bounce", as on Email, take precedence over the flat event
+// fields. The email ID falls back to the payload's id and the timestamp to
+// the event's.
+func (e *WebhookEvent) parseBounce() (*BounceEvent, error) {
+	var data struct {
+		ID         string      `json:"id"`
+		EmailID    string      `json:"email_id"`
+		Recipient  string      `json:"recipient"`
+		Type       BounceType  `json:"bounce_type"`
+		SMTPCode   int         `json:"smtp_code"`
+		Diagnostic string      `json:"diagnostic"`
+		Timestamp  time.Time   `json:"timestamp"`
+		Bounce     *BounceInfo `json:"bounce"`
+	}
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return nil, err
+	}
+
+	bounce := BounceEvent{
+		EmailID:   data.EmailID,
+		Recipient: data.Recipient,
+		SMTPCode:  data.SMTPCode,
+		BounceInfo: BounceInfo{
+			Type:           data.Type,
+			DiagnosticCode: data.Diagnostic,
+			BouncedAt:      data.Timestamp,
+		},
+	}
+	if data.Bounce != nil {
+		bounce.BounceInfo = *data.Bounce
+	}
+	if bounce.EmailID == "" {
+		bounce.EmailID = data.ID
+	}
+	if bounce.BouncedAt.IsZero() {
+		bounce.BouncedAt = e.CreatedAt
+	}
+	return &bounce, nil
+}
+
+// Is reports whether the event is of one of the given types
+func (e *WebhookEvent) Is(types ...string) bool {
+	for _, t := range types {
+		if e.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// AsEmail decodes the payload of an email.* event
+func (e *WebhookEvent) AsEmail() (*Email, bool) {
+	var email Email
+	if !e.decodeData("email.", &email) {
+		return nil, false
+	}
+	return &email, true
+}
+
+// AsSMS decodes the payload of an sms.* event
+func (e *WebhookEvent) AsSMS() (*SMS, bool) {
+	var sms SMS
+	if !e.decodeData("sms.", &sms) {
+		return nil, false
+	}
+	sms.fillEncoding()
+	return &sms, true
+}
+
+// AsCall decodes the payload of a call.* event
+func (e *WebhookEvent) AsCall() (*VoiceCall, bool) {
+	var call VoiceCall
+	if !e.decodeData("call.", &call) {
+		return nil, false
+	}
+	return &call, true
+}
+
+// decodeData decodes Data into v if the event type has the given prefix
+func (e *WebhookEvent) decodeData(prefix string, v interface{}) bool {
+	if !strings.HasPrefix(e.Type, prefix) {
+		return false
+	}
+	return json.Unmarshal(e.Data, v) == nil
+}
+
+// ParseWebhookEvent parses a webhook payload. For email.bounced events the
+// bounce details are decoded into Bounce.
+func ParseWebhookEvent(payload []byte) (*WebhookEvent, error) {
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook event: %w", err)
+	}
+	if event.Type == "" {
+		return nil, errors.New("webhook event has no type")
+	}
+
+	if event.Type == EventEmailBounced {
+		bounce, err := event.parseBounce()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bounce event: %w", err)
+		}
+		event.Bounce = bounce
+	}
+	return &event, nil
+}
+
+// ParseWebhookEventFiltered parses a webhook payload only if its type is in
+// allowed, returning ErrEventFiltered otherwise. Only the type is decoded
+// for filtered events, so unwanted events are cheap to skip.
+func ParseWebhookEventFiltered(payload []byte, allowed []string) (*WebhookEvent, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(payload, &head); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook event: %w", err)
+	}
+
+	filter := WebhookEvent{Type: head.Type}
+	if !filter.Is(allowed...) {
+		return nil, ErrEventFiltered
+	}
+
+	return ParseWebhookEvent(payload)
+}
+
+// VerifySignature checks that payload was sent by EKDSend. signatureHeader
+// is the X-EKDSend-Signature header, of the form "t=<unix time>,v1=<hex>",
+// where v1 is the HMAC-SHA256 of "<t>.<payload>" keyed with the endpoint's
+// signing secret. Several v1 values may be present while a secret is being
+// rotated; any match is accepted. Timestamps more than
+// DefaultWebhookTolerance from now are rejected to prevent replays. An
+// empty secret never verifies, since anyone can sign with it.
+//
+// Verify the raw request body, before any JSON decoding.
+func VerifySignature(payload []byte, signatureHeader string, secret string) error {
+	return VerifySignatureWithTolerance(payload, signatureHeader, secret, DefaultWebhookTolerance)
+}
+
+// VerifySignatureWithTolerance is VerifySignature with a custom timestamp
+// tolerance. A zero tolerance disables the timestamp check.
+func VerifySignatureWithTolerance(payload []byte, signatureHeader string, secret string, tolerance time.Duration) error {
+	if secret == "" {
+		return ErrInvalidSignature
+	}
+
+	var timestamp string
+	var signatures [][]byte
+
+	for _, part := range strings.Split(signatureHeader, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	matched := false
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			matched = true
+		}
+	}
+	if !matched {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		age := time.Since(time.Unix(seconds, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrSignatureExpired
+		}
+	}
+	return nil
+}
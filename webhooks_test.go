@@ -0,0 +1,128 @@
+package ekdsend
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWebhookEventBounce(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	bounced := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		payload string
+		want    BounceEvent
+	}{
+		{
+			name: "flat fields",
+			payload: `{"id":"evt_1","type":"email.bounced","created_at":"2026-01-02T03:04:05Z","data":{
+				"email_id":"em_1","recipient":"a@example.com","bounce_type":"hard","smtp_code":550,
+				"diagnostic":"mailbox unknown","timestamp":"2026-01-02T03:00:00Z"}}`,
+			want: BounceEvent{EmailID: "em_1", Recipient: "a@example.com", SMTPCode: 550, BounceInfo: BounceInfo{
+				Type: BounceHard, DiagnosticCode: "mailbox unknown", BouncedAt: bounced,
+			}},
+		},
+		{
+			name: "email payload",
+			payload: `{"id":"evt_2","type":"email.bounced","created_at":"2026-01-02T03:04:05Z","data":{
+				"id":"em_2","recipient":"b@example.com","bounce":{"type":"soft","sub_type":"mailbox_full"}}}`,
+			want: BounceEvent{EmailID: "em_2", Recipient: "b@example.com", BounceInfo: BounceInfo{
+				Type: BounceSoft, SubType: "mailbox_full", BouncedAt: created,
+			}},
+		},
+	}
+	for _, tt := range tests {
+		event, err := ParseWebhookEvent([]byte(tt.payload))
+		if err != nil {
+			t.Fatalf("%s: ParseWebhookEvent: %v", tt.name, err)
+		}
+		if event.Bounce == nil {
+			t.Fatalf("%s: Bounce is nil", tt.name)
+		}
+		if *event.Bounce != tt.want {
+			t.Errorf("%s: Bounce = %+v, want %+v", tt.name, *event.Bounce, tt.want)
+		}
+	}
+}
+
+// signWebhook returns the v1 signature of payload at timestamp t
+func signWebhook(secret string, t int64, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(t, 10) + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "whsec_test"
+	const payload = `{"id":"evt_1","type":"email.delivered","data":{}}`
+	now := time.Now().Unix()
+	ts := strconv.FormatInt(now, 10)
+	valid := signWebhook(secret, now, payload)
+
+	tests := []struct {
+		name    string
+		payload string
+		header  string
+		secret  string
+		want    error
+	}{
+		{"valid", payload, "t=" + ts + ",v1=" + valid, secret, nil},
+		{"valid with spaces", payload, "t=" + ts + ", v1=" + valid, secret, nil},
+		{"tampered payload", strings.Replace(payload, "delivered", "bounced", 1), "t=" + ts + ",v1=" + valid, secret, ErrInvalidSignature},
+		{"wrong secret", payload, "t=" + ts + ",v1=" + valid, "whsec_other", ErrInvalidSignature},
+		{"rotation, new signature second", payload, "t=" + ts + ",v1=" + signWebhook("whsec_old", now, payload) + ",v1=" + valid, secret, nil},
+		{"rotation, no match", payload, "t=" + ts + ",v1=" + signWebhook("whsec_old", now, payload) + ",v1=" + signWebhook("whsec_new", now, payload), secret, ErrInvalidSignature},
+		{"expired timestamp", payload, "t=" + strconv.FormatInt(now-3600, 10) + ",v1=" + signWebhook(secret, now-3600, payload), secret, ErrSignatureExpired},
+		{"future timestamp", payload, "t=" + strconv.FormatInt(now+3600, 10) + ",v1=" + signWebhook(secret, now+3600, payload), secret, ErrSignatureExpired},
+		{"missing timestamp", payload, "v1=" + valid, secret, ErrInvalidSignature},
+		{"missing signature", payload, "t=" + ts, secret, ErrInvalidSignature},
+		{"malformed header", payload, "garbage", secret, ErrInvalidSignature},
+		{"non-hex signature", payload, "t=" + ts + ",v1=zz", secret, ErrInvalidSignature},
+		{"empty header", payload, "", secret, ErrInvalidSignature},
+		{"empty secret", payload, "t=" + ts + ",v1=" + signWebhook("", now, payload), "", ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		err := VerifySignature([]byte(tt.payload), tt.header, tt.secret)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: VerifySignature() = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestVerifySignatureZeroToleranceSkipsTimestampCheck(t *testing.T) {
+	const payload = `{}`
+	header := "t=1000,v1=" + signWebhook("whsec_test", 1000, payload)
+	if err := VerifySignatureWithTolerance([]byte(payload), header, "whsec_test", 0); err != nil {
+		t.Errorf("VerifySignatureWithTolerance() = %v, want nil", err)
+	}
+}
+
+func TestWebhookHandlerEmptySecretRejectsRequests(t *testing.T) {
+	const payload = `{"id":"evt_1","type":"email.delivered","data":{"id":"em_1"}}`
+	now := time.Now().Unix()
+
+	called := false
+	handler := NewWebhookHandler("")
+	handler.OnEmailDelivered(func(*Email) { called = true })
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(payload))
+	req.Header.Set("X-EKDSend-Signature", "t="+strconv.FormatInt(now, 10)+",v1="+signWebhook("", now, payload))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rec.Code)
+	}
+	if called {
+		t.Error("callback ran for a request signed with an empty secret")
+	}
+}